@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- Action Log ---
+// What happened this session (renders, saves, recalls), shown in a pane above
+// the help line. It helps to keep track of which file got which font when
+// exporting many variants. The pane is part of the footer, so the layout
+// accounts for its height like for any other footer.
+
+const actionLogLines = 5 // Pane height; older actions scroll out
+
+type loggedAction struct {
+	Time time.Time
+	Text string
+}
+
+func (m *model) logAction(format string, args ...any) {
+	m.actions = append(m.actions, loggedAction{Time: time.Now(), Text: fmt.Sprintf(format, args...)})
+}
+
+// actionLogView shows the latest actions, padded to a constant height so
+// toggling is the only thing that moves the layout.
+func (m model) actionLogView() string {
+	lines := []string{listTitleStyle.Render("Session Log")}
+	start := max(len(m.actions)-actionLogLines, 0)
+	for _, a := range m.actions[start:] {
+		lines = append(lines, sourceStyle.Render(a.Time.Format("15:04:05"))+"  "+a.Text)
+	}
+	if len(m.actions) == 0 {
+		lines = append(lines, sourceStyle.Render("Nothing rendered or saved yet"))
+	}
+	for len(lines) < actionLogLines+1 {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
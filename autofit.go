@@ -0,0 +1,104 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Auto-Fit ---
+// The font "auto" is the biggest font whose render of the text fits the width
+// on one line: fonts are tried from the tallest down, and among fonts of the
+// tallest height that fits, the widest render wins, filling the width best.
+// Fonts that don't draw all of printable ASCII are skipped, as they'd "fit"
+// by leaving characters out. `--font auto` picks it on the command line, the
+// font list jumps to it with a key.
+
+const autoFontName = "auto"
+
+// autoFitFont picks the font for text at width, see above.
+func autoFitFont(figletCmdPath string, fonts []fontMetadata, text string, width int) (fontMetadata, error) {
+	candidates := slices.DeleteFunc(slices.Clone(fonts), func(f fontMetadata) bool { return f.Header.Height == 0 })
+	slices.SortStableFunc(candidates, func(a, b fontMetadata) int { return cmp.Compare(b.Header.Height, a.Header.Height) })
+
+	var best fontMetadata
+	bestWidth := -1
+	for _, f := range candidates {
+		if bestWidth >= 0 && f.Header.Height < best.Header.Height {
+			break // Only shorter fonts are left
+		}
+		if figletCmdPath != fakeFigletCmd { // Stand-in fonts draw everything but have no glyphs in their files
+			if cs, err := readFLFCharset(f.Path, f.Header); err != nil || cs.ASCII < 95 {
+				continue
+			}
+		}
+		output, err := runFiglet(figletCmdPath, f.Path, text, unwrappedWidth)
+		if err != nil {
+			continue
+		}
+		if w := measureBanner(output).Width; w <= width && w > bestWidth {
+			best, bestWidth = f, w
+		}
+	}
+	if bestWidth < 0 {
+		return fontMetadata{}, fmt.Errorf("no font fits %q on one line in %d columns", text, width)
+	}
+	return best, nil
+}
+
+// pickFontFor resolves a --font name for rendering text at width: "auto"
+// auto-fits (noted on stderr, like fuzzy matches), other names are matched
+// as by pickFontFuzzy.
+func pickFontFor(env cliEnv, name, text string, width int, rng *rand.Rand) (fontMetadata, error) {
+	if name != autoFontName {
+		return pickFontFuzzy(env.fonts, name, rng)
+	}
+	font, err := autoFitFont(env.figletCmdPath, env.fonts, text, width)
+	if err != nil {
+		return font, err
+	}
+	fmt.Fprintf(os.Stderr, "fontlet: using font %s, the biggest fitting %q in %d columns\n", font.Name, text, width)
+	return font, nil
+}
+
+type autoFitMsg struct {
+	font fontMetadata
+	err  error
+}
+
+// autoFitCmd finds the auto-fit font among the fonts in the list (the
+// filtered ones while filtering) at the full render width.
+func (m model) autoFitCmd() tea.Cmd {
+	var fonts []fontMetadata
+	for _, item := range m.fontList.VisibleItems() {
+		if f, ok := item.(fontMetadata); ok {
+			fonts = append(fonts, f)
+		}
+	}
+	figletCmdPath, text, width := m.figletCmdPath, m.renderText(), m.fullRenderWidth()
+	return func() tea.Msg {
+		font, err := autoFitFont(figletCmdPath, fonts, text, width)
+		return autoFitMsg{font, err}
+	}
+}
+
+// selectAutoFit highlights the auto-fit font in the list.
+func (m model) selectAutoFit(msg autoFitMsg) (model, tea.Cmd) {
+	if msg.err != nil {
+		return m, m.fontList.NewStatusMessage(errorStyle.Render(msg.err.Error()))
+	}
+	for i, item := range m.fontList.VisibleItems() {
+		if f, ok := item.(fontMetadata); ok && f.Path == msg.font.Path {
+			m.fontList.Select(i)
+		}
+	}
+	cmds := []tea.Cmd{m.fontList.NewStatusMessage(fmt.Sprintf("%s is the biggest font fitting %d columns", msg.font.Name, m.fullRenderWidth()))}
+	if m.highlightMode() {
+		cmds = append(cmds, m.scheduleHighlightRender())
+	}
+	return m, tea.Batch(cmds...)
+}
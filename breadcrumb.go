@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- Breadcrumb ---
+// The header shows where in the flow you are ("Text ▸ Font ▸ Output") and
+// what's being worked on, so deep screens like the filename prompt keep
+// their context.
+
+var flowSteps = []string{"Text", "Font", "Output"}
+
+// flowStep is the index into flowSteps of a state, -1 outside the flow.
+func flowStep(s appState) int {
+	switch s {
+	case stateInputText, stateSnippetPicker, stateSnippetName, stateHistoryPicker, stateTransformPicker:
+		return 0
+	case stateInitialLoading, stateSelectFontWithPreview, stateShowcase, stateGeneratingFullOutput:
+		return 1
+	case stateError:
+		return -1
+	}
+	return 2
+}
+
+// activeSnippet names the snippet the text came from, if any.
+func (m model) activeSnippet(text string) string {
+	for _, sn := range m.cfg.Snippets {
+		if sn.Text == text {
+			return sn.Name
+		}
+	}
+	return ""
+}
+
+func (m model) breadcrumbView() string {
+	state := m.state
+	if state == stateConfirmQuit {
+		state = m.quitReturnState
+	}
+	step := flowStep(state)
+	if step < 0 {
+		return ""
+	}
+
+	crumbs := make([]string, len(flowSteps))
+	for i, name := range flowSteps {
+		if i == step {
+			crumbs[i] = selectedItemStyle.Bold(true).Render(name)
+		} else {
+			crumbs[i] = sourceStyle.Render(name)
+		}
+	}
+	view := strings.Join(crumbs, sourceStyle.Render(" ▸ "))
+
+	text := m.renderText()
+	if step == 0 {
+		text = m.textInput.Value()
+	}
+	var context []string
+	if step > 0 && text != "" {
+		context = append(context, fmt.Sprintf("%q", truncate(text, 30)))
+	}
+	if step > 1 && m.selectedFontMeta.Name != "" {
+		context = append(context, "in "+m.selectedFontMeta.Name)
+	}
+	if name := m.activeSnippet(text); name != "" && text != "" {
+		context = append(context, fmt.Sprintf("(snippet %s)", name))
+	}
+	if len(context) > 0 {
+		view += sourceStyle.Render("  " + strings.Join(context, " "))
+	}
+	return view
+}
+
+// truncate shortens s to n characters, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
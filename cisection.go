@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// --- CI Sections ---
+// `fontlet ci-section "Tests"` prints a figlet banner as a skimmable section
+// header in CI logs and opens a collapsible section (a GitHub Actions group
+// or a GitLab section) for the output that follows; `--end` closes it. The
+// banner goes before the section marker so it stays visible when collapsed.
+
+const (
+	ciGitHub = "github"
+	ciGitLab = "gitlab"
+	ciPlain  = "plain"
+)
+
+// detectCI picks the syntax of the CI system fontlet runs in, if any.
+func detectCI() string {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return ciGitHub
+	case os.Getenv("GITLAB_CI") == "true":
+		return ciGitLab
+	}
+	return ciPlain
+}
+
+var gitlabSectionChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// gitlabSectionName derives a valid section name from a title, e.g. "Unit tests" -> "unit_tests".
+func gitlabSectionName(title string) string {
+	return strings.Trim(gitlabSectionChars.ReplaceAllString(strings.ToLower(title), "_"), "_")
+}
+
+// githubEscape escapes a workflow command's message, so a multi-line banner
+// stays one command.
+func githubEscape(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubPropertyEscape escapes a workflow command's property value, which
+// also ends at a "," (the next property) or ":" (the message).
+func githubPropertyEscape(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// ciSectionStart is the banner followed by the marker opening the section.
+func ciSectionStart(format, title, banner string, now time.Time) string {
+	banner = strings.TrimRight(banner, "\n") + "\n"
+	switch format {
+	case ciGitHub:
+		return banner + "::group::" + githubEscape(title) + "\n"
+	case ciGitLab:
+		return fmt.Sprintf("%s\x1b[0Ksection_start:%d:%s[collapsed=true]\r\x1b[0K%s\n", banner, now.Unix(), gitlabSectionName(title), title)
+	}
+	return banner
+}
+
+// ciSectionEnd closes the section opened for title.
+func ciSectionEnd(format, title string, now time.Time) string {
+	switch format {
+	case ciGitHub:
+		return "::endgroup::\n"
+	case ciGitLab:
+		return fmt.Sprintf("\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", now.Unix(), gitlabSectionName(title))
+	}
+	return ""
+}
+
+// ciNotice is a GitHub annotation carrying the banner, shown on the run's summary.
+func ciNotice(title, banner string) string {
+	return fmt.Sprintf("::notice title=%s::%s\n", githubPropertyEscape(title), githubEscape(strings.TrimRight(banner, "\n")))
+}
+
+func runCISection(args []string, stdout io.Writer) error {
+	fs := newFlagSet("ci-section")
+	format := fs.String("format", "auto", "marker syntax: github, gitlab, plain (banner only) or auto (detected from the environment)")
+	end := fs.Bool("end", false, "close the section opened for TITLE instead of starting one")
+	notice := fs.Bool("notice", false, "GitHub only: emit the banner as a ::notice annotation instead of a group")
+	fontName := fs.String("font", "standard", `banner font ("random" for a random one, "auto" for the biggest fitting the width)`)
+	width := fs.Int("width", 100, "banner width in columns")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("ci-section needs a title")
+	}
+	title := strings.Join(fs.Args(), " ")
+	if *format == "auto" {
+		*format = detectCI()
+	}
+	if *format != ciGitHub && *format != ciGitLab && *format != ciPlain {
+		return fmt.Errorf("unknown format %q, expected github, gitlab, plain or auto", *format)
+	}
+	if *format == ciGitLab && gitlabSectionName(title) == "" {
+		return fmt.Errorf("title %q has no characters usable in a GitLab section name", title)
+	}
+
+	if *end {
+		_, err := io.WriteString(stdout, ciSectionEnd(*format, title, time.Now()))
+		return err
+	}
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	font, err := pickFontFor(env, *fontName, title, *width, newRand(globalOptions.seed, globalOptions.seeded))
+	if err != nil {
+		return err
+	}
+	banner, err := runFiglet(env.figletCmdPath, font.Path, title, *width)
+	if err != nil {
+		return err
+	}
+	banner = trimBanner(banner)
+	if *notice && *format == ciGitHub {
+		_, err = io.WriteString(stdout, ciNotice(title, banner))
+		return err
+	}
+	_, err = io.WriteString(stdout, ciSectionStart(*format, title, banner, time.Now()))
+	return err
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestGithubEscape(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain", "plain"},
+		{"100%", "100%25"},
+		{"two\nlines", "two%0Alines"},
+		{"crlf\r\n", "crlf%0D%0A"},
+		{"a: b, c", "a: b, c"},
+	}
+	for _, tt := range tests {
+		if got := githubEscape(tt.in); got != tt.want {
+			t.Errorf("githubEscape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGithubPropertyEscape(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Tests", "Tests"},
+		{"a: b, c", "a%3A b%2C c"},
+		{"50%\n", "50%25%0A"},
+	}
+	for _, tt := range tests {
+		if got := githubPropertyEscape(tt.in); got != tt.want {
+			t.Errorf("githubPropertyEscape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
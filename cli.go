@@ -0,0 +1,320 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// --- Subcommands ---
+// Non-interactive commands run instead of the TUI, e.g. `fontlet botd`.
+
+type command struct {
+	Name    string
+	Usage   string
+	Summary string
+	Run     func(args []string, stdout io.Writer) error
+}
+
+func subcommands() []command {
+	return []command{
+		{Name: "cache", Usage: "cache warm --text TEXT [--width N] | cache clear | cache stats", Summary: "Pre-render previews for instant startup, or inspect and clear the preview cache", Run: runCache},
+		{Name: "preview", Usage: "preview [--width N] [--seed N] FONT|random|auto TEXT...", Summary: "Print one font's render of the text and its metadata", Run: runPreview},
+		{Name: "measure", Usage: "measure [--font NAME|random|auto] [--width N] [--max-width N] [--format text|json] TEXT...", Summary: "Print the width and height a text renders at, without the render, for fit checks", Run: runMeasure},
+		{Name: "compose", Usage: "compose [--width N] FILE|-", Summary: "Render a JSON composition of blocks in rows and columns, e.g. a title over a subtitle", Run: runCompose},
+		{Name: "divider", Usage: "divider [--pattern TEXT] [--width N] [--font NAME|random]", Summary: "Print a horizontal rule of a repeated pattern, or of its render in a font", Run: runDivider},
+		{Name: "fonts", Usage: "fonts grep [-i] [--sample] PATTERN | fonts import [--dir DIR] ARCHIVE | fonts list [--format text|json|tsv|names] | fonts remove [--hide] NAME | fonts restore NAME | fonts trash [--empty]", Summary: "Search fonts by name, path, header and comments, import them from archives, list them with metadata for scripts, or remove and restore them", Run: runFonts},
+		{Name: "list", Usage: "list [--json|--names]", Summary: "List the fonts with their heights, charsets and directories, like fonts list", Run: runList},
+		{Name: "packs", Usage: "packs list | packs install NAME[@VERSION]... | packs upgrade [--dry-run] [NAME...]", Summary: "Install and upgrade checksum-verified font packs from the configured manifest", Run: runPacks},
+		{Name: "gallery", Usage: "gallery --out DIR [--text TEXT] [--width N] [--pages]", Summary: "Write a searchable static HTML gallery of every font, reusing warmed previews", Run: runGallery},
+		{Name: "exec", Usage: "exec [--text TEXT] -- COMMAND [ARG...]", Summary: "Pick a font in the TUI, then run the command with {font}, {name} and {text} filled in", Run: runExec},
+		{Name: "clock", Usage: "clock [--font NAME|random] [--format LAYOUT]", Summary: "Show the current time full-screen in a figlet font", Run: runClock},
+		{Name: "timer", Usage: "timer DURATION [--font NAME|random] [--notify [--message TEXT]]", Summary: "Count down full-screen in a figlet font, flashing when time is up", Run: runTimer},
+		{Name: "sysinfo", Usage: "sysinfo [--template TEXT] [--font NAME|random] [--width N]", Summary: "Print hostname, uptime, load and IP address as stacked figlet blocks, for shell startup files", Run: runSysinfo},
+		{Name: "git", Usage: "git [--template TEXT] [--font NAME|random] [--width N]", Summary: "Print banners of the current repository's name, branch, tag or commit", Run: runGit},
+		{Name: "overlay", Usage: "overlay --out FILE --text TEXT|--input FILE|- [--font NAME|random] [--width N] [--interval DURATION] [--format text|html] [--filter NAME [--animate]]", Summary: "Keep a file up to date with a banner for OBS to show as a stream overlay", Run: runOverlay},
+		{Name: "ci-section", Usage: "ci-section [--format github|gitlab|plain|auto] [--end] [--notice] [--font NAME|random|auto] TITLE", Summary: "Print a banner as a collapsible section header in GitHub Actions or GitLab CI logs", Run: runCISection},
+		{Name: "serve", Usage: "serve [--addr HOST:PORT]", Summary: "Serve renders over HTTP, with /fonts, /healthz and Prometheus /metrics endpoints", Run: runServe},
+		{Name: "selftest", Usage: "selftest [--run NAME] [-v] [--update DIR]", Summary: "Check renders, layouts and exports of built-in fonts against golden files compiled into fontlet", Run: runSelftest},
+		{Name: "doctor", Usage: "doctor", Summary: "Check the config, the renderer and the font directories, failing if something needs fixing", Run: runDoctor},
+		{Name: "keys", Usage: "keys [--format text|md]", Summary: "Print the effective keybindings, config remaps included", Run: runKeys},
+		{Name: "botd", Usage: "botd [--text TEXT] [--date YYYY-MM-DD] [--width N] [--font NAME|random [--seed N]]", Summary: "Print the banner of the day, in a font picked from the date", Run: runBotd},
+	}
+}
+
+// runSubcommand runs the subcommand named by args[0], if there is one.
+func runSubcommand(args []string) (handled bool, err error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	for _, c := range subcommands() {
+		if c.Name == args[0] {
+			err := c.Run(args[1:], os.Stdout)
+			if errors.Is(err, flag.ErrHelp) {
+				return true, nil // Usage was already printed
+			}
+			return true, err
+		}
+	}
+	return false, nil
+}
+
+// newFlagSet creates a flag set whose usage line comes from the command table.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		for _, c := range subcommands() {
+			if c.Name == name {
+				fmt.Fprintf(fs.Output(), "Usage: fontlet %s\n\n%s.\n\n", c.Usage, c.Summary)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// cliEnv is what non-interactive commands need to render: the config,
+// figlet, and the discovered fonts.
+type cliEnv struct {
+	cfg           config
+	figletCmdPath string
+	fonts         []fontMetadata
+}
+
+func loadCLIEnv() (cliEnv, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return cliEnv{}, err
+	}
+	cmdPath, err := lookupFiglet(cfg)
+	if err != nil {
+		return cliEnv{}, err
+	}
+	fonts, err := findFigletFonts(cfg.FontDirs, false)
+	if err != nil {
+		return cliEnv{}, err
+	}
+	return cliEnv{cfg: cfg, figletCmdPath: cmdPath, fonts: applyFontAliases(fonts, cfg.FontAliases)}, nil
+}
+
+// runBotd prints the "banner of the day": the same date picks the same font on
+// every machine with the same fonts installed, so it can rotate MOTD styles from
+// shell init or cron.
+func runBotd(args []string, stdout io.Writer) error {
+	fs := newFlagSet("botd")
+	text := fs.String("text", "", "text to render (default: the hostname)")
+	date := fs.String("date", "", "pick the font for this day instead of today (YYYY-MM-DD)")
+	width := fs.Int("width", 80, "output width in columns")
+	fontName := fs.String("font", "", `use this font instead of the date's pick ("random" for a random one)`)
+	seed := fs.Uint64("seed", 0, "seed for --font random, so scripted runs pick the same font everywhere")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	day := time.Now()
+	if *date != "" {
+		var err error
+		if day, err = time.Parse(time.DateOnly, *date); err != nil {
+			return fmt.Errorf("invalid --date %q, expected YYYY-MM-DD", *date)
+		}
+	}
+	if *text == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("no --text given and the hostname is unavailable: %w", err)
+		}
+		*text = hostname
+	}
+
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	font := fontOfTheDay(env.fonts, day)
+	if *fontName != "" {
+		if font, err = pickFontFuzzy(env.fonts, *fontName, newRand(*seed, flagSet(fs, "seed"))); err != nil {
+			return err
+		}
+	}
+	output, err := runFiglet(env.figletCmdPath, font.Path, *text, *width)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(stdout, output)
+	return err
+}
+
+// runPreview renders the text in one font with a short metadata header, for
+// quick checks without the TUI.
+func runPreview(args []string, stdout io.Writer) error {
+	fs := newFlagSet("preview")
+	width := fs.Int("width", terminalWidth(), "output width in columns")
+	seed := fs.Uint64("seed", 0, "seed for the random font")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return fmt.Errorf("preview needs a font and the text to render")
+	}
+
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	text := strings.Join(fs.Args()[1:], " ")
+	font, err := pickFontFor(env, fs.Arg(0), text, *width, newRand(*seed, flagSet(fs, "seed")))
+	if err != nil {
+		return err
+	}
+	output, err := runFiglet(env.figletCmdPath, font.Path, text, *width)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s — %s\n%s\n", font.Name, shortenHome(font.Path), font.Header.summary())
+	if font.Credits.Author != "" {
+		fmt.Fprintf(stdout, "Author: %s\n", font.Credits.Author)
+	}
+	if font.Credits.License != "" {
+		fmt.Fprintf(stdout, "License: %s\n", font.Credits.License)
+	}
+	switch {
+	case font.ControlPath != "":
+		fmt.Fprintf(stdout, "Control file: %s (passed to figlet with -C)\n", shortenHome(font.ControlPath))
+	case font.Control != "":
+		fmt.Fprintf(stdout, "Control file: %s, not installed (the render may show the wrong characters)\n", font.Control)
+	}
+	fmt.Fprintln(stdout)
+	_, err = io.WriteString(stdout, output)
+	return err
+}
+
+// fontOfTheDay hashes the calendar date (not the time) into the sorted font list.
+func fontOfTheDay(fonts []fontMetadata, day time.Time) fontMetadata {
+	h := fnv.New64a()
+	h.Write([]byte(day.Format(time.DateOnly)))
+	return fonts[h.Sum64()%uint64(len(fonts))]
+}
+
+// randomFontName selects a random font wherever a font name is accepted.
+const randomFontName = "random"
+
+// pickFont resolves a font by name or alias, or picks one with rng for "random".
+func pickFont(fonts []fontMetadata, name string, rng *rand.Rand) (fontMetadata, error) {
+	if name == randomFontName {
+		return fonts[rng.IntN(len(fonts))], nil
+	}
+	for _, f := range fonts {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	for _, f := range fonts {
+		if slices.Contains(f.Aliases, name) {
+			return f, nil
+		}
+	}
+	return fontMetadata{}, fmt.Errorf("unknown font %q", name)
+}
+
+// newRand returns a generator for random font picks. Picks are reproducible
+// when a seed was given (and the same fonts are installed), random otherwise.
+func newRand(seed uint64, seeded bool) *rand.Rand {
+	if !seeded {
+		seed = uint64(time.Now().UnixNano())
+	}
+	return rand.New(rand.NewPCG(seed, 0))
+}
+
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// runCache manages the preview cache: warm renders every font's preview of a
+// sample text, so typing that text in the TUI shows the list without rendering.
+func runCache(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		newFlagSet("cache").Usage()
+		return fmt.Errorf("missing cache action: warm, clear or stats")
+	}
+	cache := openPreviewCache(defaultConfig()) // The budget only matters to warm, which has the config
+	switch args[0] {
+	case "warm":
+		fs := newFlagSet("cache")
+		text := fs.String("text", "", "text to pre-render, as it will be typed in the TUI")
+		width := fs.Int("width", terminalWidth(), "terminal width the TUI will run at")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *text == "" {
+			return fmt.Errorf("cache warm needs --text")
+		}
+		env, err := loadCLIEnv()
+		if err != nil {
+			return err
+		}
+		cache = openPreviewCache(env.cfg)
+		rendered, skipped := 0, 0
+		for _, font := range env.fonts {
+			key := previewKey(font, *text, previewWidth(*width), env.figletCmdPath)
+			if _, ok := cache.get(key); ok {
+				skipped++
+				continue
+			}
+			output, err := runFiglet(env.figletCmdPath, font.Path, *text, previewWidth(*width))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "skipping %s: %v\n", font.Name, err)
+				continue
+			}
+			if err := cache.put(key, output); err != nil {
+				return fmt.Errorf("failed to write preview cache: %w", err)
+			}
+			rendered++
+		}
+		fmt.Fprintf(stdout, "Rendered %d previews of %q at width %d (%d already cached)\n", rendered, *text, *width, skipped)
+		return nil
+	case "clear":
+		if err := cache.clear(); err != nil {
+			return fmt.Errorf("failed to clear preview cache: %w", err)
+		}
+		fmt.Fprintln(stdout, "Preview cache cleared")
+		return nil
+	case "stats":
+		entries, size, err := cache.stats()
+		if err != nil {
+			return fmt.Errorf("failed to read preview cache: %w", err)
+		}
+		counters := cache.counters()
+		hitRate := "n/a"
+		if lookups := counters.Hits + counters.Misses; lookups > 0 {
+			hitRate = fmt.Sprintf("%.1f%% (%d of %d lookups)", 100*float64(counters.Hits)/float64(lookups), counters.Hits, lookups)
+		}
+		fmt.Fprintf(stdout, "Location: %s\nEntries:  %d\nSize:     %d bytes\nHit rate: %s\n", shortenHome(cache.dir), entries, size, hitRate)
+		return nil
+	}
+	return fmt.Errorf("unknown cache action %q, expected warm, clear or stats", args[0])
+}
+
+// terminalWidth is the width of the terminal on stdout, or 80 if it isn't one.
+func terminalWidth() int {
+	if w, _, err := term.GetSize(os.Stdout.Fd()); err == nil && w > 0 {
+		return w
+	}
+	return 80
+}
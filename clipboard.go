@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Clipboard ---
+// "Clipboard" in the output menu copies the render as plain text, filtered
+// like a save, for pasting into a chat without saving a file. It's sent to
+// the terminal as an OSC 52 escape, which reaches the local clipboard over
+// SSH and, wrapped for passthrough, from inside tmux. Where a desktop
+// clipboard is at hand (pbcopy, wl-copy, xclip or xsel, clip on Windows) it's
+// copied there as well, for terminals that ignore OSC 52.
+
+type clipboardCopiedMsg struct {
+	lines int
+	tool  string // Native clipboard tool that copied it too, empty if none
+	err   error  // The tool failed
+}
+
+// copyToClipboard copies the render and stays in the output menu.
+func (m model) copyToClipboard() (model, tea.Cmd) {
+	m.logAction("Copied %s to the clipboard", m.selectedFontMeta.Name)
+	return m, clipboardCmd(stripANSI(m.outputText()))
+}
+
+func clipboardCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		fmt.Fprint(terminalOut, osc52(text))
+		msg := clipboardCopiedMsg{lines: len(bannerLines(text))}
+		if argv := nativeClipboard(); argv != nil {
+			msg.tool = argv[0]
+			cmd := exec.Command(argv[0], argv[1:]...)
+			cmd.Stdin = strings.NewReader(text) // Output isn't captured: xclip and wl-copy stay behind holding it
+			if err := cmd.Run(); err != nil {
+				msg.err = fmt.Errorf("%s failed: %w", argv[0], err)
+			}
+		}
+		return msg
+	}
+}
+
+// osc52 is the escape that sets the clipboard to text, wrapped in tmux's
+// passthrough when running inside tmux (see inlineimage.go).
+func osc52(text string) string {
+	return inTmux("\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a")
+}
+
+// nativeClipboard is the command that copies its stdin to the desktop
+// clipboard, nil without a desktop or a tool for it.
+func nativeClipboard() []string {
+	var candidates [][]string
+	switch {
+	case runtime.GOOS == "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case runtime.GOOS == "windows":
+		candidates = [][]string{{"clip"}}
+	case os.Getenv("WAYLAND_DISPLAY") != "":
+		candidates = [][]string{{"wl-copy"}}
+	case os.Getenv("DISPLAY") != "":
+		candidates = [][]string{{"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	}
+	for _, argv := range candidates {
+		if _, err := exec.LookPath(argv[0]); err == nil {
+			return argv
+		}
+	}
+	return nil
+}
+
+// notice describes the copy. Without a native tool there's no telling
+// whether the terminal took the OSC 52 escape.
+func (msg clipboardCopiedMsg) notice() string {
+	switch {
+	case msg.err != nil:
+		return errorStyle.Render(fmt.Sprintf("%v; sent %d lines to the terminal's clipboard (OSC 52) only", msg.err, msg.lines))
+	case msg.tool == "":
+		return fmt.Sprintf("Sent %d lines to the terminal's clipboard (OSC 52)", msg.lines)
+	}
+	return fmt.Sprintf("Copied %d lines to the clipboard", msg.lines)
+}
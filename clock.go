@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// --- Clock and Timer Commands ---
+// `fontlet clock` shows the current time full-screen in a figlet font,
+// `fontlet timer 10m` a countdown. --image draws them as images in
+// terminals that can show them (see inlineimage.go).
+
+func runClock(args []string, stdout io.Writer) error {
+	fs := newFlagSet("clock")
+	fontName := fs.String("font", "standard", `font to render the time in ("random" for a random one)`)
+	format := fs.String("format", "15:04:05", "Go time layout, e.g. 15:04 or 3:04 PM")
+	image := fs.Bool("image", false, imageFlagUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("clock takes no arguments")
+	}
+	protocol, err := imageFlag(*image)
+	if err != nil {
+		return err
+	}
+
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	font, err := pickFontFuzzy(env.fonts, *fontName, newRand(globalOptions.seed, globalOptions.seeded))
+	if err != nil {
+		return err
+	}
+	m, err := newDisplayModel(env, font, func(now time.Time) string { return now.Format(*format) })
+	if err != nil {
+		return err
+	}
+	m.image = protocol
+	return runDisplay(m)
+}
+
+// runTimer counts down full-screen and flashes the banner once time is up,
+// optionally sending a notification too.
+func runTimer(args []string, stdout io.Writer) error {
+	fs := newFlagSet("timer")
+	fontName := fs.String("font", "standard", `font to render the countdown in ("random" for a random one)`)
+	notify := fs.Bool("notify", false, "send a desktop notification when time is up (see notify_command in the config)")
+	message := fs.String("message", "Time's up", "text of the notification banner")
+	image := fs.Bool("image", false, imageFlagUsage)
+	var durationArg string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") { // Allow flags after the duration
+		durationArg, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if durationArg == "" && fs.NArg() == 1 {
+		durationArg = fs.Arg(0)
+	} else if durationArg == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("timer needs exactly one duration, e.g. 10m or 1h30m")
+	}
+	duration, err := time.ParseDuration(durationArg)
+	if err != nil || duration <= 0 {
+		return fmt.Errorf("invalid duration %q, expected e.g. 90s, 10m or 1h30m", durationArg)
+	}
+	protocol, err := imageFlag(*image)
+	if err != nil {
+		return err
+	}
+
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	font, err := pickFontFuzzy(env.fonts, *fontName, newRand(globalOptions.seed, globalOptions.seeded))
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(duration)
+	m, err := newDisplayModel(env, font, func(now time.Time) string { return formatCountdown(deadline.Sub(now)) })
+	if err != nil {
+		return err
+	}
+	m.image = protocol
+	m.alert = func(now time.Time) bool { return !now.Before(deadline) }
+	if *notify {
+		m.onAlert = notifyCmd(env, fmt.Sprintf("fontlet timer %s", durationArg), *message)
+	}
+	return runDisplay(m)
+}
+
+// formatCountdown shows the time left as "04:59" or "1:04:59", rounding up
+// so the display reads 00:00 exactly when time is up.
+func formatCountdown(left time.Duration) string {
+	secs := int((max(left, 0) + time.Second - 1) / time.Second)
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
+}
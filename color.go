@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// --- Color ---
+// All styles come from renderer, which is tied to stdout, and messages on
+// stderr go through stderrRenderer, so each stream only gets escapes when it
+// is a terminal. --color=always/never overrides the detection.
+
+var (
+	renderer       = lipgloss.NewRenderer(os.Stdout)
+	stderrRenderer = lipgloss.NewRenderer(os.Stderr)
+	stdoutColors   bool   // Output printed to stdout may be colored
+	detectColors   = true // No --color override
+)
+
+// setColorMode applies --color. It also makes renderer the default, so
+// bubbles components that create their own styles follow it too.
+func setColorMode(mode string) error {
+	colorful := renderer.ColorProfile() // What stdout supports
+	if colorful == termenv.Ascii {
+		colorful = termenv.ANSI256
+	}
+	switch mode {
+	case "auto":
+	case "always":
+		renderer.SetColorProfile(colorful)
+		stderrRenderer.SetColorProfile(colorful)
+	case "never":
+		renderer.SetColorProfile(termenv.Ascii)
+		stderrRenderer.SetColorProfile(termenv.Ascii)
+	default:
+		return fmt.Errorf("invalid --color %q, expected auto, always or never", mode)
+	}
+	lipgloss.SetDefaultRenderer(renderer)
+	stdoutColors = renderer.ColorProfile() != termenv.Ascii
+	detectColors = mode == "auto"
+	return nil
+}
+
+// useTerminalColors points renderer at tty, where the TUI draws while stdout
+// is redirected, for its colors and background; --color still wins.
+func useTerminalColors(tty *os.File) {
+	out := termenv.NewOutput(tty)
+	renderer.SetOutput(out)
+	if detectColors {
+		renderer.SetColorProfile(out.EnvColorProfile())
+	}
+}
+
+// stderrError renders an error message for stderr.
+func stderrError(msg string) string {
+	return errorStyle.Renderer(stderrRenderer).Render(msg)
+}
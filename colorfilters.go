@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Color Filters ---
+// The metal and rainbow filters color the banner the way toilet's --metal and
+// --gay do, without needing toilet: every drawn character gets a color from a
+// palette, picked by its position so the colors run in diagonal bands. The
+// result is ANSI escapes, shown in the terminal view and saved with colors
+// kept (Ctrl+T on the filename screen), or converted by the HTML and IRC
+// export formats. Each frame shifts the bands a step, for the animated
+// overlay (see overlay.go).
+
+// ANSI foreground codes of toilet's palettes, in libcaca's colors.
+var (
+	metalPalette   = []int{94, 34, 37, 90}         // Light blue, blue, light gray, dark gray
+	rainbowPalette = []int{95, 91, 93, 92, 96, 94} // Light magenta, red, yellow, green, cyan, blue
+)
+
+// colorFilterFrames are the color filters by name, drawing a given frame.
+var colorFilterFrames = map[string]func(s string, frame int) string{
+	"metal":   metalFrame,
+	"rainbow": rainbowFrame,
+}
+
+func metalFilter(s string) string   { return metalFrame(s, 0) }
+func rainbowFilter(s string) string { return rainbowFrame(s, 0) }
+
+// metalFrame shades the banner in blues and grays, in bands of two rows
+// that slope every eight columns.
+func metalFrame(s string, frame int) string {
+	return colorCells(s, func(x, y int) int { return metalPalette[(y+x/8+frame)/2%len(metalPalette)] })
+}
+
+// rainbowFrame cycles through the rainbow diagonally, a color per row and
+// per two columns.
+func rainbowFrame(s string, frame int) string {
+	return colorCells(s, func(x, y int) int { return rainbowPalette[(x/2+y+frame)%len(rainbowPalette)] })
+}
+
+// colorCells colors every non-blank character with the code color picks for
+// its cell. x counts terminal cells, so wide characters take two. A code is
+// only written where the color changes, and each colored line ends with a
+// reset. Colors already in s are replaced.
+func colorCells(s string, color func(x, y int) int) string {
+	lines := strings.Split(stripANSI(s), "\n")
+	for y, line := range lines {
+		var b strings.Builder
+		x, current := 0, 0
+		for _, r := range line {
+			if r != ' ' {
+				if c := color(x, y); c != current {
+					fmt.Fprintf(&b, "\x1b[%dm", c)
+					current = c
+				}
+			}
+			b.WriteRune(r)
+			x += lipgloss.Width(string(r))
+		}
+		if current != 0 {
+			b.WriteString("\x1b[0m")
+		}
+		lines[y] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Composition Screen ---
+// "Compose" in the output menu adds the full render as a block of a
+// composition and opens this screen, where blocks are arranged with the
+// layout engine. Esc goes back to the font list to render the next block;
+// Enter makes the composition the output, to save or view like a render.
+
+type composedBlock struct {
+	Font    string      // Font name, for the status line
+	Output  string      // Unfiltered render; the output filter applies to the whole composition
+	Divider string      // Pattern of a divider block, which spans the other blocks instead
+	Align   layoutAlign // Overrides the composition's alignment for this block, "" follows it
+}
+
+type composition struct {
+	Blocks    []composedBlock
+	Direction layoutDirection
+	Align     layoutAlign
+	Spacing   int
+	Selected  int // Block that moves or is removed
+}
+
+// composedOutput lays the blocks out as currently arranged.
+func (c composition) composedOutput() string {
+	blocks := make([]string, len(c.Blocks))
+	aligns := make([]layoutAlign, len(c.Blocks))
+	widest := 0
+	for i, b := range c.Blocks {
+		aligns[i] = cmp.Or(b.Align, c.Align)
+		blocks[i] = trimBanner(b.Output)
+		widest = max(widest, measureBanner(blocks[i]).Width)
+	}
+	for i, b := range c.Blocks {
+		if b.Divider != "" {
+			blocks[i] = makeDivider(b.Divider, max(widest, 20))
+		}
+	}
+	return composeAligned(blocks, aligns, c.Direction, c.Spacing)
+}
+
+// blockName describes a block in the status line.
+func (b composedBlock) blockName() string {
+	if b.Divider != "" {
+		return fmt.Sprintf("divider %q", b.Divider)
+	}
+	return b.Font
+}
+
+// alignName names an alignment for the direction, e.g. "center" or "top".
+func (c composition) alignName(align layoutAlign) string {
+	names := map[layoutAlign][2]string{alignStart: {"left", "top"}, alignCenter: {"center", "middle"}, alignEnd: {"right", "bottom"}}
+	if c.Direction == layoutRow {
+		return names[align][1]
+	}
+	return names[align][0]
+}
+
+// addToComposition appends the current render and opens the composition screen.
+func (m model) addToComposition() (model, tea.Cmd) {
+	if m.composition.Direction == "" {
+		m.composition.Direction, m.composition.Align = layoutColumn, alignCenter
+	}
+	m.composition.Blocks = append(m.composition.Blocks, composedBlock{Font: m.selectedFontMeta.Name, Output: m.fullFigletOutput})
+	m.composition.Selected = len(m.composition.Blocks) - 1
+	m.logAction("Added %s to the composition", m.selectedFontMeta.Name)
+	m.figletViewport = viewport.New(m.termWidth-docStyle.GetHorizontalFrameSize(), m.termHeight-lipgloss.Height(m.headerView())-lipgloss.Height(m.footerView())-4)
+	m.figletViewport.Style = figletOutputStyle
+	m.state = stateCompose
+	return m.refreshComposition(), nil
+}
+
+// refreshComposition re-lays out the blocks and describes the arrangement.
+func (m model) refreshComposition() model {
+	c := m.composition
+	m.figletViewport.SetContent(c.composedOutput())
+	if len(c.Blocks) == 0 {
+		m.statusMessage = "The composition is empty. Esc to render a block."
+		return m
+	}
+	selected := c.Blocks[c.Selected].blockName()
+	if align := c.Blocks[c.Selected].Align; align != "" {
+		selected += ", " + c.alignName(align)
+	}
+	m.statusMessage = fmt.Sprintf("%d blocks · %s · %s · spacing %d · block %d (%s) selected",
+		len(c.Blocks), c.Direction, c.alignName(c.Align), c.Spacing, c.Selected+1, selected)
+	return m
+}
+
+// updateCompose handles keys on the composition screen. Like other dialogs'
+// answers these aren't remappable.
+func (m model) updateCompose(msg tea.KeyMsg) (model, tea.Cmd) {
+	c := &m.composition
+	switch msg.String() {
+	case "d":
+		if c.Direction == layoutRow {
+			c.Direction = layoutColumn
+		} else {
+			c.Direction = layoutRow
+		}
+	case "a":
+		c.Align = map[layoutAlign]layoutAlign{alignStart: alignCenter, alignCenter: alignEnd, alignEnd: alignStart}[c.Align]
+	case "A": // The selected block's own alignment, back to the composition's after a full cycle
+		if len(c.Blocks) > 0 {
+			b := &c.Blocks[c.Selected]
+			b.Align = map[layoutAlign]layoutAlign{"": alignStart, alignStart: alignCenter, alignCenter: alignEnd, alignEnd: ""}[b.Align]
+		}
+	case "+", "=":
+		c.Spacing++
+	case "-":
+		c.Spacing = max(c.Spacing-1, 0)
+	case "[":
+		c.Selected = max(c.Selected-1, 0)
+	case "]":
+		c.Selected = max(min(c.Selected+1, len(c.Blocks)-1), 0)
+	case "<", ">":
+		to := c.Selected - 1
+		if msg.String() == ">" {
+			to = c.Selected + 1
+		}
+		if to >= 0 && to < len(c.Blocks) {
+			c.Blocks[c.Selected], c.Blocks[to] = c.Blocks[to], c.Blocks[c.Selected]
+			c.Selected = to
+		}
+	case "r": // Add a divider below the selected block, or change the selected divider's pattern
+		if len(c.Blocks) > 0 && c.Blocks[c.Selected].Divider != "" {
+			b := &c.Blocks[c.Selected]
+			b.Divider = dividerPatterns[(slices.Index(dividerPatterns, b.Divider)+1)%len(dividerPatterns)]
+			break
+		}
+		at := min(c.Selected+1, len(c.Blocks))
+		c.Blocks = slices.Insert(c.Blocks, at, composedBlock{Divider: dividerPatterns[0]})
+		c.Selected = at
+	case "x":
+		if len(c.Blocks) > 0 {
+			c.Blocks = append(c.Blocks[:c.Selected], c.Blocks[c.Selected+1:]...)
+			c.Selected = max(min(c.Selected, len(c.Blocks)-1), 0)
+		}
+	case "enter":
+		if len(c.Blocks) == 0 {
+			return m, nil
+		}
+		m.fullFigletOutput = c.composedOutput()
+		m.composed = true
+		m.unsaved = true
+		m.logAction("Composed %d blocks", len(c.Blocks))
+		return m.toOutputChoice(), nil
+	case "esc":
+		m.statusMessage = ""
+		m.state = stateSelectFontWithPreview
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.figletViewport, cmd = m.figletViewport.Update(msg) // Scrolling
+		return m, cmd
+	}
+	return m.refreshComposition(), nil
+}
+
+// composeView shows the arrangement above the composed document.
+func (m model) composeView() string {
+	return statusMessageStyle.Render(m.statusMessage) + "\n\n" + m.figletViewport.View()
+}
+
+// composeHelp lists the composition screen's keys.
+func (m model) composeHelp() string {
+	return helpView(infoBinding("d", "row/col"), infoBinding("a", "align"), infoBinding("A", "align block"), infoBinding("+/-", "spacing"),
+		infoBinding("r", "divider"), infoBinding("[/]", "select"), infoBinding("</>", "move"), infoBinding("x", "remove"),
+		infoBinding("enter", "output"), infoBinding("esc", "add block"), m.keys.Quit)
+}
+
+// errComposedScript is returned when saving a composition as a shell script,
+// which can only regenerate a single render.
+var errComposedScript = errors.New("shell scripts regenerate a single render, save the composition in another format")
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// --- Config ---
+// Settings are read from config.json in the user's config directory
+// (e.g. ~/.config/fontlet/config.json). A missing file or field falls back to the defaults.
+
+type config struct {
+	SSHBannerPath string              `json:"ssh_banner_path"`         // Target of the SSH banner export preset
+	IssueEscapes  string              `json:"issue_escapes,omitempty"` // Getty escapes appended to /etc/issue exports, e.g. "\\S \\n \\l"
+	MaxWidth      int                 `json:"max_width,omitempty"`     // Warn before saving output wider than this, 0 disables the check
+	FontDirs      []string            `json:"font_dirs,omitempty"`     // Extra font directories, searched before the system one
+	Theme         themeConfig         `json:"theme"`
+	Keys          map[string][]string `json:"keys,omitempty"`              // Action name -> keys, see keyMap.named
+	PreviewMode   string              `json:"preview_mode"`                // "bulk" renders every preview up front, "highlight" only the highlighted font
+	Snippets      []snippet           `json:"snippets,omitempty"`          // Frequently used texts, editable from the snippet picker
+	HistorySize   int                 `json:"history_size,omitempty"`      // Recent outputs kept, 0 means the default and -1 disables history
+	CharLimit     int                 `json:"char_limit,omitempty"`        // Maximum input length in characters, 0 for no limit
+	ConfirmQuit   bool                `json:"confirm_quit"`                // Ask before quitting with a render that was never saved
+	PasteJoin     string              `json:"paste_join"`                  // Separator for the lines of multi-line pastes
+	FileMode      string              `json:"file_mode"`                   // Octal permissions of newly saved files, e.g. "0600"
+	ScriptExec    bool                `json:"script_executable"`           // Make shell script exports executable
+	NotifyCommand []string            `json:"notify_command,omitempty"`    // Run by timer --notify, with {title} and {banner} filled in
+	NotifyFont    string              `json:"notify_font,omitempty"`       // Font of the notification banner, "small" by default
+	PackManifest  string              `json:"pack_manifest,omitempty"`     // URL or file listing the font packs `fontlet packs` installs
+	PackMirror    string              `json:"pack_mirror,omitempty"`       // URL or directory with a copy of the manifest's directory, used instead
+	Footer        footerConfig        `json:"footer"`                      // Block added under saved banners, see footer.go
+	CenterWidth   int                 `json:"center_width,omitempty"`      // Center saved banners within this many columns, 0 disables
+	FontAliases   map[string]string   `json:"font_aliases,omitempty"`      // Alias -> font name, usable wherever a font name is
+	BellAfter     int                 `json:"bell_after,omitempty"`        // Seconds previews or a save must take to ring the bell, 0 means the default and -1 never
+	Renderer      string              `json:"renderer,omitempty"`          // "builtin" (default) or "figlet" to render with the figlet command, see figfont.go
+	PreviewMemory int                 `json:"preview_memory_mb,omitempty"` // MiB of previews kept in memory, 0 means the default, see previewstore.go
+	PreviewCache  int                 `json:"preview_cache_mb,omitempty"`  // MiB of previews kept on disk, 0 means the default, see previewcache.go
+	FontSort      string              `json:"font_sort,omitempty"`         // "collate" (default), "natural" or "bytes", see fontsort.go
+}
+
+// themeConfig holds lipgloss colors ("62", "#ff8700"); empty fields keep the default.
+type themeConfig struct {
+	Title    string `json:"title,omitempty"`
+	Help     string `json:"help,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Success  string `json:"success,omitempty"`
+	Output   string `json:"output,omitempty"`   // Figlet output in the terminal view
+	Selected string `json:"selected,omitempty"` // Highlighted font in the list
+	Status   string `json:"status,omitempty"`
+	Spinner  string `json:"spinner,omitempty"`
+}
+
+func defaultConfig() config {
+	return config{
+		SSHBannerPath: "/etc/issue.net",
+		PreviewMode:   previewModeBulk,
+		ConfirmQuit:   true,
+		PasteJoin:     " ",
+		FileMode:      "0644",
+		ScriptExec:    true,
+	}
+}
+
+// fileMode parses FileMode, which loadConfig has validated.
+func (c config) fileMode() fs.FileMode {
+	mode, err := strconv.ParseUint(c.FileMode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0644
+	}
+	return fs.FileMode(mode)
+}
+
+func configPath() (string, error) {
+	if noState {
+		return "", errNoState
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "fontlet", "config.json"), nil
+}
+
+// configModTime is used to watch the config for changes; zero if it doesn't exist.
+func configModTime() time.Time {
+	path, err := configPath()
+	if err != nil {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func loadConfig() (config, error) {
+	cfg := defaultConfig()
+	if noState {
+		if data := os.Getenv(configEnv); data != "" {
+			return parseConfig(cfg, []byte(data), "$"+configEnv)
+		}
+		return cfg, nil
+	}
+	path, err := configPath()
+	if err != nil {
+		return cfg, nil // No config dir (e.g. $HOME unset), run with defaults
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	return parseConfig(cfg, data, path)
+}
+
+// parseConfig reads config JSON from path (or another source) over the defaults in cfg.
+func parseConfig(cfg config, data []byte, path string) (config, error) {
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.PreviewMode != previewModeBulk && cfg.PreviewMode != previewModeHighlight {
+		return cfg, fmt.Errorf("invalid config %s: preview_mode must be %q or %q", path, previewModeBulk, previewModeHighlight)
+	}
+	if cfg.CharLimit < 0 {
+		return cfg, fmt.Errorf("invalid config %s: char_limit must be 0 (no limit) or positive", path)
+	}
+	if mode, err := strconv.ParseUint(cfg.FileMode, 8, 32); err != nil || mode > 0777 {
+		return cfg, fmt.Errorf("invalid config %s: file_mode must be octal permissions like \"0644\", got %q", path, cfg.FileMode)
+	}
+	if err := validateFontAliases(cfg.FontAliases); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.BellAfter < -1 {
+		return cfg, fmt.Errorf("invalid config %s: bell_after must be seconds, 0 for the default or -1 to never ring", path)
+	}
+	if cfg.FontSort != "" && cfg.FontSort != fontSortCollate && cfg.FontSort != fontSortNatural && cfg.FontSort != fontSortBytes {
+		return cfg, fmt.Errorf("invalid config %s: font_sort must be %q, %q or %q", path, fontSortCollate, fontSortNatural, fontSortBytes)
+	}
+	if cfg.PreviewMemory < 0 {
+		return cfg, fmt.Errorf("invalid config %s: preview_memory_mb must be 0 (the default) or positive", path)
+	}
+	if cfg.PreviewCache < 0 {
+		return cfg, fmt.Errorf("invalid config %s: preview_cache_mb must be 0 (the default) or positive", path)
+	}
+	if cfg.Renderer != "" && cfg.Renderer != rendererBuiltin && cfg.Renderer != rendererFiglet {
+		return cfg, fmt.Errorf("invalid config %s: renderer must be %q or %q", path, rendererBuiltin, rendererFiglet)
+	}
+	if cfg.CenterWidth < 0 {
+		return cfg, fmt.Errorf("invalid config %s: center_width must be 0 (off) or positive", path)
+	}
+	if _, err := parseLayoutAlign(cfg.Footer.Align); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: footer align: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// saveConfigKey sets one top-level key of the config file to value, e.g.
+// after editing snippets in the TUI. The rest of the file is kept as it is
+// on disk, with edits made since it was loaded and without the defaults of
+// keys the user never set. With --no-state nothing is written and the
+// change lasts for the session.
+func saveConfigKey(key string, value any) error {
+	if noState {
+		return nil
+	}
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	case len(bytes.TrimSpace(data)) > 0:
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return fmt.Errorf("invalid config %s, not changing it: %w", path, err)
+		}
+	}
+	if fields[key], err = json.Marshal(value); err != nil {
+		return err
+	}
+	if data, err = json.MarshalIndent(fields, "", "  "); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := writeAtomic(path, 0644, func(f *os.File) error {
+		_, err := f.Write(append(data, '\n'))
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to write config %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSaveConfigKeyKeepsTheRestOfTheFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	path := filepath.Join(dir, "fontlet", "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"font_dirs": ["~/fonts"], "theme": {"accent": "212"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveConfigKey("snippets", []snippet{{Name: "warn", Text: "WARNING"}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"font_dirs": `["~/fonts"]`,
+		"theme":     `{"accent":"212"}`,
+		"snippets":  `[{"name":"warn","text":"WARNING"}]`,
+	}
+	if len(got) != len(want) {
+		t.Errorf("config has keys %v, want only %v", slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(want)))
+	}
+	for k, v := range want {
+		if g := compactJSON(t, got[k]); g != v {
+			t.Errorf("%s = %s, want %s", k, g, v)
+		}
+	}
+}
+
+func TestSaveConfigKeyNoState(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	noState = true
+	defer func() { noState = false }()
+	if err := saveConfigKey("snippets", []snippet{}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("wrote %d entries with --no-state, want none", len(entries))
+	}
+}
+
+func compactJSON(t *testing.T, raw json.RawMessage) string {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// --- Control Files ---
+// A few fonts only draw sensibly through a figlet control file (.flc) that
+// maps the typed characters onto the ones the font defines: tsalagi draws the
+// Cherokee syllabary, moscow Cyrillic, katakana kana. Without it figlet
+// renders the wrong glyphs or nothing. The control file a font needs comes
+// from its comments ("use with -C tsalagi.flc") or the table below; when it's
+// found next to the font or in a font directory, renders pass it with -C.
+
+// knownControlFiles are the control files of fonts whose comments don't say.
+var knownControlFiles = map[string]string{
+	"tsalagi":  "tsalagi.flc",
+	"moscow":   "moscow.flc",
+	"katakana": "uskata.flc",
+	"morse":    "upper.flc", // Morse fonts only draw capitals
+	"morse2":   "upper.flc",
+}
+
+var controlMention = regexp.MustCompile(`(?i)[\w.-]+\.flc\b`)
+
+// controlFileName is the control file the font needs, or "" for most fonts.
+func controlFileName(name, comments string) string {
+	if mention := controlMention.FindString(comments); mention != "" {
+		return mention
+	}
+	return knownControlFiles[strings.ToLower(name)]
+}
+
+// findControlFile looks for the control file next to the font, then in the
+// font directories. Returns "" if it isn't installed.
+func findControlFile(control, fontPath string, fontDirs []string) string {
+	for _, dir := range append([]string{filepath.Dir(fontPath)}, fontDirs...) {
+		path := filepath.Join(dir, control)
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			return path
+		}
+	}
+	return ""
+}
+
+// fontControlFiles maps font paths to the control file passed to figlet with
+// them. Discovery records it, so every render of the font uses it.
+var fontControlFiles sync.Map
+
+// controlAnnotation notes the control file in the font list.
+func (fm fontMetadata) controlAnnotation() string {
+	switch {
+	case fm.Control == "":
+		return ""
+	case fm.ControlPath == "":
+		return "  needs " + fm.Control + ", not installed"
+	}
+	return "  with " + fm.Control
+}
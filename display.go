@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Full-Screen Display ---
+// The clock and timer modes show one banner centered on the alt screen and
+// re-render it when its text changes. Ticks are aligned to the wall clock's
+// seconds, so a clock turns over when the second does, not up to a second late.
+// Once alert reports true (the timer ran out) the banner flashes inverted.
+// With --image the banner is drawn as an image (see inlineimage.go) at the
+// same place, carried by the escapes of the view's last line so the
+// renderer redraws it after clearing the screen; it flashes by blinking.
+
+type displayModel struct {
+	figletCmdPath string
+	font          fontMetadata
+	keys          keyMap
+	text          func(now time.Time) string // The banner's text at a given time
+	alert         func(now time.Time) bool   // Optional, whether to flash the banner
+	onAlert       tea.Cmd                    // Optional, run once when the alert starts
+	alerting      bool
+	flash         bool   // Inverted phase of the flashing
+	notice        string // Shown under the banner, e.g. a failed notification
+	rendered      string // Text of output
+	output        string
+	image         imageProtocol // Draw the banner as an image, imageNone for text
+	fg            [3]int        // Color of the image
+	inline        string        // Escape drawing output as an image
+	width, height int
+}
+
+type displayTickMsg time.Time
+type displayRenderedMsg struct {
+	text, output, inline string
+	width                int
+}
+
+// displayImageID is the kitty image the banner is drawn as, replaced by
+// each new render.
+const displayImageID = 1
+
+func newDisplayModel(env cliEnv, font fontMetadata, text func(time.Time) string) (displayModel, error) {
+	keys, err := newKeyMap(env.cfg.Keys)
+	if err != nil {
+		return displayModel{}, err
+	}
+	applyTheme(env.cfg.Theme)
+	return displayModel{figletCmdPath: env.figletCmdPath, font: font, keys: keys, text: text, fg: bannerRGB(env.cfg.Theme)}, nil
+}
+
+func (m displayModel) Init() tea.Cmd {
+	return displayTick()
+}
+
+// displayTick fires at the start of the next second.
+func displayTick() tea.Cmd {
+	now := time.Now()
+	return tea.Tick(now.Truncate(time.Second).Add(time.Second).Sub(now), func(t time.Time) tea.Msg { return displayTickMsg(t) })
+}
+
+func (m displayModel) renderCmd(text string) tea.Cmd {
+	width := max(m.width, 20)
+	return func() tea.Msg {
+		output, err := runFiglet(m.figletCmdPath, m.font.Path, text, width)
+		if err != nil {
+			return displayRenderedMsg{text: text, output: err.Error(), width: width}
+		}
+		msg := displayRenderedMsg{text: text, output: output, width: width}
+		if m.image != imageNone {
+			msg.inline, err = m.inlineBanner(trimBanner(output))
+			if err != nil {
+				msg.output = err.Error()
+			}
+		}
+		return msg
+	}
+}
+
+// inlineBanner is the escape drawing banner as an image where View would
+// center its text, returning the cursor to where it was.
+func (m displayModel) inlineBanner(banner string) (string, error) {
+	data, err := bannerPNG(banner, m.fg)
+	if err != nil {
+		return "", err
+	}
+	cols, rows := lipgloss.Width(banner), len(bannerLines(banner))
+	row, col := max((m.height-rows)/2, 0)+1, max((m.width-cols)/2, 0)+1
+	return fmt.Sprintf("\x1b7\x1b[%d;%dH%s\x1b8", row, col, m.image.inline(data, displayImageID, cols, rows)), nil
+}
+
+func (m displayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, m.renderCmd(m.text(time.Now()))
+	case displayTickMsg:
+		cmds := []tea.Cmd{displayTick()}
+		wasAlerting := m.alerting
+		m.alerting = m.alert != nil && m.alert(time.Time(msg))
+		m.flash = m.alerting && !m.flash
+		if m.alerting && !wasAlerting && m.onAlert != nil {
+			cmds = append(cmds, m.onAlert)
+		}
+		if text := m.text(time.Time(msg)); text != m.rendered && m.width > 0 {
+			cmds = append(cmds, m.renderCmd(text))
+		}
+		return m, tea.Batch(cmds...)
+	case displayRenderedMsg:
+		m.rendered, m.output, m.inline = msg.text, msg.output, msg.inline
+	case notifyDoneMsg:
+		if msg.err != nil {
+			m.notice = msg.err.Error()
+		}
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Quit, m.keys.CloseView) {
+			return m, tea.Quit
+		}
+		if key.Matches(msg, m.keys.Suspend) {
+			return m, tea.Suspend
+		}
+	}
+	return m, nil
+}
+
+func (m displayModel) View() string {
+	if m.width == 0 {
+		return ""
+	}
+	if m.image != imageNone && m.inline != "" {
+		return m.imageView()
+	}
+	style := figletOutputStyle
+	if m.flash {
+		style = style.Reverse(true)
+	}
+	view := style.Render(trimBanner(m.output))
+	if m.notice != "" {
+		view = lipgloss.JoinVertical(lipgloss.Center, view, "", errorStyle.Render(m.notice))
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, view)
+}
+
+// imageView leaves the screen blank but for the notice, on the last line
+// with the escapes that clear the screen above it and draw the image.
+func (m displayModel) imageView() string {
+	last := fmt.Sprintf("\x1b7\x1b[%d;%dH\x1b[1J\x1b8", max(m.height-1, 1), m.width) + m.image.clear(displayImageID)
+	if !m.flash {
+		last += m.inline
+	}
+	if m.notice != "" {
+		last += lipgloss.PlaceHorizontal(m.width, lipgloss.Center, errorStyle.Render(m.notice))
+	}
+	return strings.Repeat("\n", max(m.height-1, 0)) + last
+}
+
+// trimBanner drops the trailing blank lines figlet leaves, so centering
+// isn't thrown off by them.
+func trimBanner(output string) string {
+	return truncateString(output, len(bannerLines(output)))
+}
+
+// runDisplay shows m until the user quits.
+func runDisplay(m displayModel) error {
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	forwardSuspendSignals(p)
+	stopWatching := killOnHangup(p)
+	_, err := p.Run()
+	stopWatching()
+	shutdown()
+	return err
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Dividers ---
+// Horizontal rules to go with banners: a pattern like "=", "─" or "-~"
+// repeated to a width, or with a font the pattern's render repeated, for a
+// rule several lines tall. `fontlet divider` prints one, compositions take
+// them as blocks that span the other blocks.
+
+// dividerPatterns are cycled through on the composition screen.
+var dividerPatterns = []string{"─", "═", "=", "-", "~", "*", "· "}
+
+// cutColumns shortens s to at most width terminal columns.
+func cutColumns(s string, width int) string {
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String()
+}
+
+// tileBlock repeats a block side by side and cuts it to width columns.
+func tileBlock(block string, width int) string {
+	lines, blockWidth := blockShape(block)
+	if blockWidth == 0 || width <= 0 {
+		return ""
+	}
+	for i, line := range lines {
+		line += strings.Repeat(" ", blockWidth-lipgloss.Width(line)) // Keep repeats aligned
+		lines[i] = strings.TrimRight(cutColumns(strings.Repeat(line, width/blockWidth+1), width), " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// makeDivider repeats pattern to width columns, as a one-line rule.
+func makeDivider(pattern string, width int) string {
+	return tileBlock(pattern, width)
+}
+
+// renderedDivider repeats the pattern's render in a font to width columns.
+func renderedDivider(figletCmdPath, fontPath, pattern string, width int) (string, error) {
+	output, err := runFiglet(figletCmdPath, fontPath, pattern, unwrappedWidth)
+	if err != nil {
+		return "", err
+	}
+	return tileBlock(trimBanner(output), width), nil
+}
+
+func runDivider(args []string, stdout io.Writer) error {
+	fs := newFlagSet("divider")
+	pattern := fs.String("pattern", "─", "characters to repeat, e.g. = or -~")
+	width := fs.Int("width", terminalWidth(), "width of the rule in columns")
+	fontName := fs.String("font", "", `render the pattern in this font and repeat that ("random" for a random one)`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("divider takes no arguments")
+	}
+	if strings.TrimSpace(*pattern) == "" || strings.ContainsAny(*pattern, "\r\n") {
+		return fmt.Errorf("--pattern needs at least one visible character and no line breaks")
+	}
+
+	rule := makeDivider(*pattern, *width)
+	if *fontName != "" {
+		env, err := loadCLIEnv()
+		if err != nil {
+			return err
+		}
+		font, err := pickFontFuzzy(env.fonts, *fontName, newRand(globalOptions.seed, globalOptions.seeded))
+		if err != nil {
+			return err
+		}
+		if rule, err = renderedDivider(env.figletCmdPath, font.Path, *pattern, *width); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(stdout, rule)
+	return err
+}
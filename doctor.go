@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+)
+
+// --- Diagnostics ---
+// `fontlet doctor`, and d on the error screen, check what rendering depends
+// on: the config, the renderer and every font directory, saying what was
+// found where. Checks that fail are what to fix; the others are for context.
+
+type doctorCheck struct {
+	Name, Detail string
+	OK           bool
+}
+
+// doctorChecks runs the checks. It loads the config itself, so it works when
+// the config is what's broken.
+func doctorChecks() []doctorCheck {
+	var checks []doctorCheck
+	cfg, err := loadConfig()
+	switch path, pathErr := configPath(); {
+	case err != nil:
+		checks = append(checks, doctorCheck{"config", err.Error(), false})
+		cfg = defaultConfig() // Check the rest as if there were none
+	case pathErr != nil:
+		checks = append(checks, doctorCheck{"config", "from $" + configEnv + " (no state)", true})
+	case configModTime().IsZero():
+		checks = append(checks, doctorCheck{"config", shortenHome(path) + " (not created, defaults apply)", true})
+	default:
+		checks = append(checks, doctorCheck{"config", shortenHome(path), true})
+	}
+
+	switch cmdPath, err := lookupFiglet(cfg); {
+	case err != nil:
+		checks = append(checks, doctorCheck{"renderer", err.Error(), false})
+	case cmdPath == builtinFigletCmd:
+		checks = append(checks, doctorCheck{"renderer", "built-in", true})
+	default:
+		checks = append(checks, doctorCheck{"renderer", "figlet at " + shortenHome(cmdPath), true})
+	}
+
+	for _, dir := range cfg.FontDirs {
+		checks = append(checks, fontDirCheck("font dir", expandHome(dir), "missing"))
+	}
+	if dir, err := packsDir(); err == nil {
+		checks = append(checks, fontDirCheck("packs", dir, ""))
+	}
+	if dir, err := importedFontsDir(); err == nil {
+		checks = append(checks, fontDirCheck("imported", dir, ""))
+	}
+	if dir := systemFontDir(); dir != "" {
+		checks = append(checks, fontDirCheck("system fonts", dir, "missing"))
+	} else {
+		checks = append(checks, doctorCheck{"system fonts", "not found (figlet's fonts aren't installed)", true})
+	}
+
+	if fonts, err := findFigletFonts(cfg.FontDirs, false); err != nil {
+		checks = append(checks, doctorCheck{"fonts", err.Error(), false})
+	} else {
+		checks = append(checks, doctorCheck{"fonts", fmt.Sprintf("%d available", len(fonts)), true})
+	}
+	return checks
+}
+
+// fontDirCheck counts the fonts in dir. A missing dir fails with problem, or
+// is just noted when problem is empty, e.g. for packs that were never installed.
+func fontDirCheck(name, dir, problem string) doctorCheck {
+	info, err := os.Stat(dir)
+	switch {
+	case err != nil && problem == "":
+		return doctorCheck{name, shortenHome(dir) + " (none yet)", true}
+	case err != nil:
+		return doctorCheck{name, shortenHome(dir) + ": " + problem, false}
+	case !info.IsDir():
+		return doctorCheck{name, shortenHome(dir) + ": not a directory", false}
+	}
+	n := 0
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && isFontFile(path) {
+			n++
+		}
+		return nil // Unreadable parts just aren't counted
+	})
+	return doctorCheck{name, fmt.Sprintf("%s (%d fonts)", shortenHome(dir), n), true}
+}
+
+// writeDoctorChecks lists checks one per line, failures marked.
+func writeDoctorChecks(w io.Writer, checks []doctorCheck) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range checks {
+		status := "ok"
+		if !c.OK {
+			status = "FAIL"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", status, c.Name, c.Detail)
+	}
+	return tw.Flush()
+}
+
+// runDoctor prints the checks, failing if any did, so scripts can gate on it.
+func runDoctor(args []string, stdout io.Writer) error {
+	fs := newFlagSet("doctor")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	checks := doctorChecks()
+	if err := writeDoctorChecks(stdout, checks); err != nil {
+		return err
+	}
+	failed := 0
+	for _, c := range checks {
+		if !c.OK {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
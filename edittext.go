@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Inline Text Editing ---
+// Ctrl+E opens a small editor for the text over the font list, the showcase,
+// the output menu or the output view. Enter re-renders where you are: the
+// output of the chosen font, or the previews, which are replaced in the
+// background while the list keeps its selection and filter. That saves going
+// back to the text screen, which starts over with a fresh list.
+
+var editModalStyle = renderer.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+
+// canEditText reports whether the current screen takes inline edits.
+// Compositions are left alone, they'd be replaced by a single render.
+func (m model) canEditText() bool {
+	switch m.state {
+	case stateSelectFontWithPreview:
+		return m.fontList.FilterState() != list.Filtering
+	case stateShowcase:
+		return true
+	case stateOutputChoice, stateDisplayFiglet:
+		return !m.composed
+	}
+	return false
+}
+
+// editTextHelp is the editor's key, disabled where compositions are shown.
+func (m model) editTextHelp() key.Binding {
+	b := m.keys.EditText
+	b.SetEnabled(m.canEditText())
+	return b
+}
+
+// openEditText shows the editor with the current text.
+func (m model) openEditText() (model, tea.Cmd) {
+	ti := textinput.New()
+	ti.Prompt = "Text: "
+	ti.PromptStyle = inputPromptStyle
+	ti.TextStyle = inputValueStyle
+	ti.CharLimit = m.textInput.CharLimit
+	ti.Width = max(min(m.termWidth-12, 60), 10)
+	ti.SetValue(m.inputText)
+	ti.CursorEnd()
+	m.editInput = ti
+	m.editingText = true
+	return m, m.editInput.Focus()
+}
+
+// updateEditText handles keys while the editor is open.
+func (m model) updateEditText(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.editingText = false
+		return m, nil
+	case key.Matches(msg, m.keys.Confirm):
+		text := strings.TrimSpace(m.editInput.Value())
+		m.editingText = false
+		if text == "" || text == m.inputText {
+			return m, nil
+		}
+		m.inputText = text
+		m.logAction("Changed the text to %q", text)
+		return m.rerenderText()
+	}
+	var cmd tea.Cmd
+	m.editInput, cmd = m.editInput.Update(msg)
+	return m, cmd
+}
+
+// rerenderText renders the edited text for the current screen.
+func (m model) rerenderText() (model, tea.Cmd) {
+	m.highlightRenders = make(map[string]string)
+	m.showcaseRenders = nil
+	m.widthRenders = nil
+	var cmds []tea.Cmd
+	if m.highlightMode() {
+		m.previewed = previewTarget{text: m.renderText()}
+	} else {
+		var cmd tea.Cmd
+		m, cmd = m.streamPreviews() // Into the list, also behind other screens
+		cmds = append(cmds, cmd)
+	}
+	switch {
+	case m.state == stateOutputChoice || m.state == stateDisplayFiglet: // fullFigletRenderedMsg refreshes the screen
+		cmds = append(cmds, m.renderFullFigletCmd(m.selectedFontMeta.Path, m.renderText()))
+	case m.state == stateShowcase:
+		cmds = append(cmds, m.ensureShowcaseRender())
+	case m.highlightMode():
+		cmds = append(cmds, m.scheduleHighlightRender())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// editTextView draws the editor over the middle of the screen.
+func (m model) editTextView(screen string) string {
+	box := editModalStyle.Render(m.editInput.View() + "\n" + helpView(describe(m.keys.Confirm, "re-render"), describe(m.keys.Back, "cancel")))
+	lines := strings.Split(screen, "\n")
+	boxLines := strings.Split(lipgloss.PlaceHorizontal(m.termWidth, lipgloss.Center, box), "\n")
+	top := max((len(lines)-len(boxLines))/2, 0)
+	for i, line := range boxLines {
+		if top+i < len(lines) {
+			lines[top+i] = line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Error Screen ---
+// An error that stops an operation shows what can be done about it rather
+// than only quitting: r retries the operation, where that makes sense, f adds
+// a font directory when fonts couldn't be found, d shows the diagnostics of
+// `fontlet doctor`, and c carries on without what failed, back where the
+// error struck (with no fonts, the list is empty). q or Esc quits.
+
+// errorMsg stops the current operation and shows the error screen.
+type errorMsg struct {
+	err   error
+	retry func(m model) tea.Cmd // Runs the failed operation again, nil if it can't be
+	fonts bool                  // Font discovery failed, another font directory may help
+}
+
+type diagnosticsMsg struct{ checks []doctorCheck }
+
+// showError switches to the error screen for msg.
+func (m model) showError(msg errorMsg) model {
+	if m.state != stateError { // A second error keeps the place of the first
+		m.errorState = m.state
+	}
+	m.errorMessage = msg.err.Error()
+	m.errorRetry = msg.retry
+	m.errorFonts = msg.fonts
+	m.errorFontDir = false
+	m.diagnostics = nil
+	m.state = stateError
+	return m
+}
+
+// updateError handles keys on the error screen.
+func (m model) updateError(msg tea.KeyMsg) (model, tea.Cmd) {
+	if m.errorFontDir {
+		switch {
+		case key.Matches(msg, m.keys.Confirm):
+			return m.addFontDir(strings.TrimSpace(m.fontDirInput.Value()))
+		case key.Matches(msg, m.keys.Back):
+			m.errorFontDir = false
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.fontDirInput, cmd = m.fontDirInput.Update(msg)
+		return m, cmd
+	}
+	switch msg.String() {
+	case "r":
+		if m.errorRetry != nil {
+			return m.retryAfterError()
+		}
+	case "f":
+		if m.errorFonts {
+			ti := textinput.New()
+			ti.Prompt = "Font directory: "
+			ti.PromptStyle = inputPromptStyle
+			ti.TextStyle = inputValueStyle
+			ti.Placeholder = "~/fonts"
+			ti.Width = max(min(m.termWidth-24, 60), 10)
+			m.fontDirInput = ti
+			m.errorFontDir = true
+			return m, m.fontDirInput.Focus()
+		}
+	case "d":
+		if m.diagnostics != nil {
+			m.diagnostics = nil
+			return m, nil
+		}
+		return m, func() tea.Msg { return diagnosticsMsg{doctorChecks()} }
+	case "c":
+		return m.continueAfterError()
+	case "q", "esc":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// retryAfterError goes back where the error struck and runs the operation again.
+func (m model) retryAfterError() (model, tea.Cmd) {
+	m.state = m.errorState
+	cmd := m.errorRetry(m)
+	if m.state == stateInitialLoading || m.state == stateGeneratingFullOutput {
+		cmd = tea.Batch(m.spinner.Tick, cmd)
+	}
+	return m, cmd
+}
+
+// continueAfterError goes back where the error struck, or to the nearest
+// screen that works without what failed.
+func (m model) continueAfterError() (model, tea.Cmd) {
+	m.state = m.errorState
+	switch m.errorState {
+	case stateInitialLoading, stateInputText:
+		if m.errorFonts && !m.fontsLoaded {
+			m.fontsLoaded = true // With no fonts
+			if m.state == stateInitialLoading {
+				return m.startPreviews()
+			}
+		}
+	case stateGeneratingFullOutput:
+		m.state = stateSelectFontWithPreview
+	case stateSaving:
+		m.saving = nil
+		m.state = stateSaveFileNameInput
+		return m, m.textInput.Focus()
+	}
+	return m, nil
+}
+
+// continueHelp names where c leads.
+func (m model) continueHelp() string {
+	switch {
+	case m.errorFonts && !m.fontsLoaded:
+		return "continue without fonts"
+	case m.errorState == stateGeneratingFullOutput:
+		return "back to font list"
+	case m.errorState == stateSaving:
+		return "back to filename"
+	}
+	return "continue"
+}
+
+// addFontDir adds dir to font_dirs, saving the config, and retries finding fonts.
+func (m model) addFontDir(dir string) (model, tea.Cmd) {
+	if dir == "" {
+		return m, nil
+	}
+	if info, err := os.Stat(expandHome(dir)); err != nil || !info.IsDir() {
+		return m, m.showNotice(errorStyle.Render(fmt.Sprintf("%s is not a directory", dir)))
+	}
+	cfg := m.cfg
+	cfg.FontDirs = append(append([]string(nil), cfg.FontDirs...), dir)
+	var cmds []tea.Cmd
+	if err := saveConfigKey("font_dirs", cfg.FontDirs); err != nil { // Still used for this session
+		cmds = append(cmds, m.showNotice(errorStyle.Render(fmt.Sprintf("%s is used until you quit: %v", dir, err))))
+	} else {
+		m.configModTime = configModTime() // Our own write, not an external edit to reload
+	}
+	m.cfg = cfg
+	m.errorFontDir = false
+	m, cmd := m.retryAfterError()
+	return m, tea.Batch(append(cmds, cmd)...)
+}
+
+// errorView shows the error, the diagnostics when asked for, and the font
+// directory input.
+func (m model) errorView() string {
+	var s strings.Builder
+	s.WriteString(errorStyle.Render(m.errorMessage))
+	if m.diagnostics != nil {
+		s.WriteString("\n\n")
+		writeDoctorChecks(&s, m.diagnostics)
+	}
+	if m.errorFontDir {
+		s.WriteString("\n\n")
+		s.WriteString(m.fontDirInput.View())
+	}
+	return s.String()
+}
+
+// errorHelp lists the actions that apply to the error.
+func (m model) errorHelp() string {
+	if m.errorFontDir {
+		return helpView(describe(m.keys.Confirm, "add and retry"), describe(m.keys.Back, "cancel"), m.keys.Quit)
+	}
+	var bindings []key.Binding
+	if m.errorRetry != nil {
+		bindings = append(bindings, infoBinding("r", "retry"))
+	}
+	if m.errorFonts {
+		bindings = append(bindings, infoBinding("f", "add font directory"))
+	}
+	diagnostics := "diagnostics"
+	if m.diagnostics != nil {
+		diagnostics = "hide diagnostics"
+	}
+	bindings = append(bindings, infoBinding("d", diagnostics), infoBinding("c", m.continueHelp()), infoBinding("q/esc", "quit"))
+	return helpView(bindings...)
+}
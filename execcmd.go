@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// --- Exec Command ---
+// `fontlet exec -- figlet -f {font} Hello` is a font picker in front of any
+// figlet-based command: the TUI runs until a font is chosen, then the
+// placeholders in the command are filled in and it runs with the terminal.
+
+func runExec(args []string, stdout io.Writer) error {
+	fs := newFlagSet("exec")
+	text := fs.String("text", "", "text to preview fonts with, instead of typing it in the TUI")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("exec needs a command to run")
+	}
+
+	opts := globalOptions
+	opts.text = strings.TrimSpace(*text)
+	opts.pickOnly = true
+	final, err := runTUI(opts)
+	if err != nil {
+		return err
+	}
+	if final.pickedFont.Path == "" {
+		return fmt.Errorf("no font chosen, %s was not run", fs.Arg(0))
+	}
+
+	argv := expandExecTemplate(fs.Args(), final.pickedFont, final.renderText())
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", argv[0], err)
+	}
+	return nil
+}
+
+// expandExecTemplate fills in {font} (the font file), {name} and {text} in
+// every argument. Arguments are passed to the command as they are, without a
+// shell, so paths with spaces stay one argument.
+func expandExecTemplate(template []string, font fontMetadata, text string) []string {
+	r := strings.NewReplacer("{font}", font.Path, "{name}", font.Name, "{text}", text)
+	argv := make([]string, len(template))
+	for i, arg := range template {
+		argv[i] = r.Replace(arg)
+	}
+	return argv
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Exporters ---
+// Export formats wrap the (filtered) banner for a destination before it is saved.
+
+type exportFormat struct {
+	Name         string
+	Extension    string                  // Used for the suggested filename
+	DefaultPath  func(cfg config) string // Optional system file the preset writes to
+	MaxWidth     int                     // Optional width limit checked before saving
+	MessageLimit int                     // Chat presets: characters per message, longer banners are split
+	Binary       bool                    // Content is bytes: line endings and the BOM don't apply, overwrites aren't diffed
+	Export       func(banner string, cfg config) (string, error)
+	Script       func(spec renderSpec) string // Saves how to regenerate the banner instead, Export is unused
+}
+
+var exportFormats = []exportFormat{
+	{Name: "plain text", Extension: ".txt", Export: plain(func(s string) string { return s })},
+	{Name: "C string array", Extension: ".h", Export: plain(exportCStringArray)},
+	{Name: "C byte array", Extension: ".h", Export: plain(exportCByteArray)},
+	{Name: "YAML (cloud-init write_files)", Extension: ".yaml", Export: plain(exportCloudInit)},
+	{Name: "YAML (Ansible var)", Extension: ".yml", Export: plain(exportYAMLVar)},
+	{Name: "/etc/issue (getty)", Extension: "", DefaultPath: func(config) string { return "/etc/issue" }, MaxWidth: loginBannerMaxWidth, Export: exportIssue},
+	{Name: "SSH banner", Extension: "", DefaultPath: func(cfg config) string { return cfg.SSHBannerPath }, MaxWidth: loginBannerMaxWidth, Export: exportSSHBanner},
+	{Name: "Discord message", Extension: ".md", MessageLimit: discordMessageLimit, Export: plain(chatExporter(discordMessageLimit))},
+	{Name: "Slack message", Extension: ".md", MessageLimit: slackMessageLimit, Export: plain(chatExporter(slackMessageLimit))},
+	{Name: "IRC (mIRC colors)", Extension: ".txt", Export: plain(exportIRC)},
+	{Name: "HTML (pre block)", Extension: ".html", Export: plain(exportHTML)},
+	{Name: "PNG image", Extension: ".png", Binary: true, Export: exportPNG},
+	{Name: "shell script (regenerates the banner)", Extension: ".sh", Script: exportScript},
+}
+
+// plain adapts an exporter that needs no config and can't fail.
+func plain(export func(string) string) func(string, config) (string, error) {
+	return func(banner string, _ config) (string, error) { return export(banner), nil }
+}
+
+// Login banners are shown on consoles and SSH clients that can't be assumed
+// to be wider than this.
+const loginBannerMaxWidth = 80
+
+// loginBanner strips colors and checks the width limit shared by the
+// /etc/issue and SSH banner presets.
+func loginBanner(banner, preset string) (string, error) {
+	banner = stripANSI(banner)
+	if w := lipgloss.Width(banner); w > loginBannerMaxWidth {
+		return "", fmt.Errorf("output is %d columns wide, %s banners are limited to %d", w, preset, loginBannerMaxWidth)
+	}
+	return banner, nil
+}
+
+// exportIssue prepares the banner for /etc/issue. getty treats backslashes as
+// escape sequences, so literal ones are doubled; the configured escapes are
+// appended verbatim.
+func exportIssue(banner string, cfg config) (string, error) {
+	banner, err := loginBanner(banner, "/etc/issue")
+	if err != nil {
+		return "", err
+	}
+	banner = strings.ReplaceAll(banner, `\`, `\\`)
+	if !strings.HasSuffix(banner, "\n") {
+		banner += "\n"
+	}
+	if cfg.IssueEscapes != "" {
+		banner += cfg.IssueEscapes + "\n"
+	}
+	return banner, nil
+}
+
+// exportSSHBanner prepares the banner for sshd's Banner file, which is sent as-is.
+func exportSSHBanner(banner string, _ config) (string, error) {
+	banner, err := loginBanner(banner, "SSH")
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(banner, "\n") {
+		banner += "\n"
+	}
+	return banner, nil
+}
+
+// saveOptions are toggled on the filename screen and apply to every export format.
+type saveOptions struct {
+	Colors bool // Keep ANSI escape sequences instead of saving plain text
+	CRLF   bool // Windows line endings
+	BOM    bool // Prefix a UTF-8 byte order mark, for tools that need it to detect the encoding
+	Footer bool // Add the configured footer block, see footer.go
+	Center bool // Center the banner within the configured center_width
+}
+
+// encode applies the line ending and BOM options to exported content.
+func (o saveOptions) encode(content string) string {
+	if o.CRLF {
+		content = strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+	}
+	if o.BOM {
+		content = "\ufeff" + content
+	}
+	return content
+}
+
+// bannerLines splits a banner into lines without the trailing empty line
+// left by the final newline.
+func bannerLines(banner string) []string {
+	return strings.Split(strings.TrimSuffix(banner, "\n"), "\n")
+}
+
+// cEscape quotes s as a C string literal. Non-ASCII bytes use fixed-width octal
+// escapes so they can't swallow the characters that follow.
+func cEscape(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\' || c == '?': // '?' avoids accidental trigraphs
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// exportCStringArray emits the banner as a header with one string per line,
+// for firmware that prints its boot banner line by line.
+func exportCStringArray(banner string) string {
+	lines := bannerLines(banner)
+
+	var b strings.Builder
+	b.WriteString("/* Generated by fontlet */\n")
+	b.WriteString("#ifndef FONTLET_BANNER_H\n#define FONTLET_BANNER_H\n\n")
+	b.WriteString("static const char *banner[] = {\n")
+	for _, line := range lines {
+		fmt.Fprintf(&b, "\t%s,\n", cEscape(line))
+	}
+	b.WriteString("};\n")
+	fmt.Fprintf(&b, "static const unsigned int banner_lines = %d;\n", len(lines))
+	b.WriteString("\n#endif /* FONTLET_BANNER_H */\n")
+	return b.String()
+}
+
+// exportCByteArray emits the banner as a NUL-terminated unsigned char blob.
+// banner_len excludes the terminator.
+func exportCByteArray(banner string) string {
+	const perLine = 12
+
+	var b strings.Builder
+	b.WriteString("/* Generated by fontlet */\n")
+	b.WriteString("#ifndef FONTLET_BANNER_H\n#define FONTLET_BANNER_H\n\n")
+	b.WriteString("static const unsigned char banner[] = {")
+	data := append([]byte(banner), 0)
+	for i, c := range data {
+		if i%perLine == 0 {
+			b.WriteString("\n\t")
+		} else {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(&b, "0x%02x,", c)
+	}
+	b.WriteString("\n};\n")
+	fmt.Fprintf(&b, "static const unsigned int banner_len = %d;\n", len(banner))
+	b.WriteString("\n#endif /* FONTLET_BANNER_H */\n")
+	return b.String()
+}
+
+// yamlScalar renders banner as a YAML value whose content sits at the given
+// indentation (in spaces) relative to parentIndent. Banners normally become a
+// literal block scalar with an explicit indentation indicator, since figlet
+// lines often start with spaces and would otherwise confuse indentation
+// detection. The chomping indicator keeps the text's exact ending: "+" keeps
+// trailing blank lines and "-" drops the newline a banner didn't end with.
+// Text with control characters (e.g. ANSI escapes) can't appear in a literal
+// block, so it falls back to a double-quoted scalar.
+func yamlScalar(banner string, parentIndent, indent int) string {
+	for _, r := range banner {
+		if r != '\n' && r != '\t' && (r < 0x20 || r == 0x7f) {
+			return strconv.Quote(banner)
+		}
+	}
+
+	chomp := "" // Clip: exactly one final newline
+	switch {
+	case strings.HasSuffix(banner, "\n\n"):
+		chomp = "+"
+	case !strings.HasSuffix(banner, "\n"):
+		chomp = "-"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "|%d%s\n", indent, chomp)
+	prefix := strings.Repeat(" ", parentIndent+indent)
+	for _, line := range bannerLines(banner) {
+		if line != "" {
+			b.WriteString(prefix)
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// exportCloudInit emits a cloud-config document that writes the banner to /etc/motd.
+func exportCloudInit(banner string) string {
+	var b strings.Builder
+	b.WriteString("#cloud-config\n")
+	b.WriteString("# Generated by fontlet\n")
+	b.WriteString("write_files:\n")
+	b.WriteString("  - path: /etc/motd\n")
+	b.WriteString("    permissions: '0644'\n")
+	b.WriteString("    content: ")
+	b.WriteString(yamlScalar(banner, 4, 2))
+	return b.String()
+}
+
+// exportYAMLVar emits the banner as a top-level variable, e.g. for Ansible vars files.
+func exportYAMLVar(banner string) string {
+	return "# Generated by fontlet\nbanner: " + yamlScalar(banner, 0, 2)
+}
+
+// Message length limits in characters. Slack accepts longer messages but
+// folds them behind "Show more" past this.
+const (
+	discordMessageLimit = 2000
+	slackMessageLimit   = 4000
+)
+
+// chatExporter wraps the banner in code blocks for pasting into chat, one
+// per message: banners over limit are split between lines into several
+// blocks, separated by blank lines.
+func chatExporter(limit int) func(string) string {
+	return func(banner string) string {
+		return strings.Join(chatMessages(banner, limit), "\n\n") + "\n"
+	}
+}
+
+// chatMessages splits the banner into code blocks of at most limit
+// characters. A single line too long for a message gets one of its own.
+func chatMessages(banner string, limit int) []string {
+	const fence = "```"
+	banner = strings.ReplaceAll(stripANSI(banner), fence, "`\u200b``") // Don't end the block early
+	var messages, block []string
+	size := 2*len(fence) + 2 // Fences and their newlines
+	for _, line := range bannerLines(banner) {
+		n := utf8.RuneCountInString(line) + 1
+		if len(block) > 0 && size+n > limit {
+			messages = append(messages, fence+"\n"+strings.Join(block, "\n")+"\n"+fence)
+			block, size = nil, 2*len(fence)+2
+		}
+		block = append(block, line)
+		size += n
+	}
+	return append(messages, fence+"\n"+strings.Join(block, "\n")+"\n"+fence)
+}
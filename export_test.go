@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestYAMLScalar(t *testing.T) {
+	tests := []struct {
+		banner string
+		want   string
+	}{
+		{" _\n|_|\n", "|2\n   _\n  |_|\n"},
+		{" _\n|_|\n\n\n", "|2+\n   _\n  |_|\n\n\n"}, // Blank lines kept
+		{" _\n|_|", "|2-\n   _\n  |_|\n"},
+		{"a\n\nb\n", "|2\n  a\n\n  b\n"},
+		{"\x1b[31mred\x1b[0m\n", `"\x1b[31mred\x1b[0m\n"`},
+	}
+	for _, tt := range tests {
+		if got := yamlScalar(tt.banner, 0, 2); got != tt.want {
+			t.Errorf("yamlScalar(%q) = %q, want %q", tt.banner, got, tt.want)
+		}
+	}
+}
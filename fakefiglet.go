@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --- Fake Figlet ---
+// With --fake-figlet, fonts are rendered by a tiny deterministic renderer
+// instead of figlet, from a handful of stand-in fonts. Output only depends on
+// the font, text and width, so end-to-end TUI tests get predictable screens and
+// contributors can work on fontlet without figlet installed.
+
+// fakeFigletCmd stands in for the figlet path; runFiglet renders in-process for it.
+const fakeFigletCmd = "fontlet-fake-figlet"
+
+var useFakeFiglet bool // Set by --fake-figlet
+
+type fakeFont struct {
+	Name   string
+	Height int
+	Width  int  // Columns per character, odd so the letter sits in the middle
+	Ink    rune // Fills the character cell around the letter
+}
+
+var fakeFonts = []fakeFont{
+	{Name: "fake-block", Height: 3, Width: 3, Ink: '#'},
+	{Name: "fake-thin", Height: 1, Width: 1, Ink: ' '},
+	{Name: "fake-tall", Height: 5, Width: 3, Ink: '|'},
+	{Name: "fake-wide", Height: 3, Width: 5, Ink: '='},
+}
+
+// fakeFontDir writes the stand-in fonts as real .flf files (header and a
+// comment only), so discovery, the index and font editing work unchanged.
+func fakeFontDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil || noState {
+		base = os.TempDir()
+	}
+	dir := filepath.Join(base, "fontlet", "fake-fonts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	for _, f := range fakeFonts {
+		path := filepath.Join(dir, f.Name+".flf")
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		content := fmt.Sprintf("flf2a$ %d %d %d 0 1\nStand-in font rendered by fontlet --fake-figlet, not by figlet.\n", f.Height, f.Height, f.Width+2)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// renderFake draws every character as a Height x Width cell of ink with the
+// character itself in the middle, wrapping to fit width like figlet does.
+func renderFake(fontPath, text string, width int) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(fontPath), filepath.Ext(fontPath))
+	var font fakeFont
+	for _, f := range fakeFonts {
+		if f.Name == name {
+			font = f
+		}
+	}
+	if font.Name == "" {
+		return "", fmt.Errorf("%s is not a --fake-figlet font", fontPath)
+	}
+
+	runes := []rune(text)
+	perLine := max((width+1)/(font.Width+1), 1)
+	ink := strings.Repeat(string(font.Ink), font.Width/2)
+	var b strings.Builder
+	for start := 0; start < len(runes); start += perLine {
+		chunk := runes[start:min(start+perLine, len(runes))]
+		for row := 0; row < font.Height; row++ {
+			cells := make([]string, len(chunk))
+			for i, r := range chunk {
+				switch {
+				case r == ' ':
+					cells[i] = strings.Repeat(" ", font.Width)
+				case row == font.Height/2:
+					cells[i] = ink + string(r) + ink
+				default:
+					cells[i] = strings.Repeat(string(font.Ink), font.Width)
+				}
+			}
+			b.WriteString(strings.TrimRight(strings.Join(cells, " "), " "))
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderFake(t *testing.T) {
+	tests := []struct {
+		font  string
+		text  string
+		width int
+		want  string
+	}{
+		{"fake-thin", "Hi", 80, "H i\n"},
+		{"fake-block", "Hi", 80, "### ###\n#H# #i#\n### ###\n"},
+		{"fake-block", "a b", 80, "###     ###\n#a#     #b#\n###     ###\n"},
+		{"fake-wide", "ab", 80, "===== =====\n==a== ==b==\n===== =====\n"},
+		{"fake-thin", "abc", 3, "a b\nc\n"},                       // Wraps at the width
+		{"fake-block", "ab", 1, "###\n#a#\n###\n###\n#b#\n###\n"}, // At least one per line
+		{"fake-thin", "", 80, ""},
+	}
+	for _, tt := range tests {
+		// Through runFiglet, as the renderer picked by --fake-figlet
+		got, err := runFiglet(fakeFigletCmd, "/fonts/"+tt.font+".flf", tt.text, tt.width)
+		if err != nil {
+			t.Errorf("runFiglet(%s, %q, %d) error %v", tt.font, tt.text, tt.width, err)
+		} else if got != tt.want {
+			t.Errorf("runFiglet(%s, %q, %d) = %q, want %q", tt.font, tt.text, tt.width, got, tt.want)
+		}
+	}
+	if _, err := runFiglet(fakeFigletCmd, "/fonts/standard.flf", "Hi", 80); err == nil {
+		t.Error("runFiglet with a font that isn't a stand-in succeeded")
+	}
+}
+
+func TestLookupFigletFake(t *testing.T) {
+	defer func(old bool) { useFakeFiglet = old }(useFakeFiglet)
+	useFakeFiglet = true
+	got, err := lookupFiglet(config{Renderer: rendererFiglet}) // Even when figlet is asked for
+	if err != nil || got != fakeFigletCmd {
+		t.Errorf("lookupFiglet with --fake-figlet = %q, %v, want %q", got, err, fakeFigletCmd)
+	}
+}
+
+// TestFakeFontDir checks the stand-in fonts are written as valid font files,
+// so discovery finds them like any other font.
+func TestFakeFontDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir()) // os.UserCacheDir on macOS
+	dir, err := fakeFontDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range fakeFonts {
+		file, err := os.Open(filepath.Join(dir, f.Name+".flf"))
+		if err != nil {
+			t.Errorf("%s: %v", f.Name, err)
+			continue
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Scan()
+		h, err := parseFLFHeader(scanner.Text())
+		file.Close()
+		if err != nil {
+			t.Errorf("%s: header %q: %v", f.Name, scanner.Text(), err)
+		} else if h.Height != f.Height {
+			t.Errorf("%s: header height %d, want %d", f.Name, h.Height, f.Height)
+		}
+	}
+}
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// --- Built-in Renderer ---
+// Fonts are rendered in-process by a port of figlet's own algorithm: glyphs
+// are read from the .flf file, hardblanks keep their space until output, and
+// letters are kerned or smushed together by the rules in the font's header,
+// wrapping at word boundaries like figlet does. So fontlet runs without figlet
+// installed. The figlet command is only used with "renderer": "figlet" in the
+// config, or for fonts the built-in renderer can't draw, when it's installed:
+// fonts needing a control file (see controlfile.go) and compressed fonts.
+
+// builtinFigletCmd stands in for the figlet path; runFiglet renders in-process for it.
+const builtinFigletCmd = "fontlet-builtin"
+
+const (
+	rendererBuiltin = "builtin"
+	rendererFiglet  = "figlet"
+)
+
+// lookupFiglet picks the renderer: the fake one in fake mode, the figlet
+// command if the config asks for it, else the built-in one.
+func lookupFiglet(cfg config) (string, error) {
+	switch {
+	case useFakeFiglet:
+		return fakeFigletCmd, nil
+	case cfg.Renderer == rendererFiglet:
+		cmdPath, err := exec.LookPath("figlet")
+		if err != nil {
+			return "", fmt.Errorf("figlet command not found. Install figlet, or remove \"renderer\": %q from the config to use the built-in renderer", rendererFiglet)
+		}
+		return cmdPath, nil
+	}
+	return builtinFigletCmd, nil
+}
+
+// Smushing rules, the bits of the header's layout fields.
+const (
+	smushEqual     = 1 << iota // Equal characters merge
+	smushLowline               // An underscore gives way to |/\[]{}()<>
+	smushHierarchy             // Of |, /\, [], {}, (), <> the later class wins
+	smushPair                  // Opposite brackets become |
+	smushBigX                  // /\ becomes |, \/ Y and >< X
+	smushHardblank             // Two hardblanks merge
+	smushKern                  // Letters touch without merging
+	smushSmush                 // Letters merge by the rules above, all of them if none is set
+)
+
+// figFont is a FIGfont loaded for rendering.
+type figFont struct {
+	header flfHeader
+	layout int               // Smushing rules
+	glyphs map[rune][][]rune // Character -> Height rows of equal width
+}
+
+// layoutRules is how letters are fitted together, from the full layout if
+// the header has one, else from the old layout: 0 kerns, -1 is full width.
+func (h flfHeader) layoutRules() int {
+	switch {
+	case h.FullLayout >= 0:
+		return h.FullLayout & 0xff
+	case h.OldLayout == 0:
+		return smushKern
+	case h.OldLayout < 0:
+		return 0
+	}
+	return h.OldLayout&31 | smushSmush
+}
+
+// flfDeutsch are the characters of the 7 glyphs after ASCII in every FIGfont.
+var flfDeutsch = []rune{'Ä', 'Ö', 'Ü', 'ä', 'ö', 'ü', 'ß'}
+
+// parseFIGfont reads a FIGfont. A font truncated after its header still
+// loads, missing glyphs render as nothing like in figlet.
+func parseFIGfont(r io.Reader) (*figFont, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("empty font file")
+	}
+	h, err := parseFLFHeader(scanner.Text())
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < h.CommentLines; i++ {
+		if !scanner.Scan() {
+			return &figFont{header: h, layout: h.layoutRules(), glyphs: make(map[rune][][]rune)}, scanner.Err()
+		}
+	}
+
+	f := &figFont{header: h, layout: h.layoutRules(), glyphs: make(map[rune][][]rune)}
+	// glyph reads the next character's rows, without endmarks
+	glyph := func() ([][]rune, bool) {
+		rows := make([][]rune, h.Height)
+		width := 0
+		for i := range rows {
+			if !scanner.Scan() {
+				return nil, false
+			}
+			line := strings.TrimRight(scanner.Text(), " \t\r\v\f")
+			if line != "" {
+				line = strings.TrimRight(line, line[len(line)-1:]) // Endmarks, usually @
+			}
+			rows[i] = flfRunes(line)
+			width = max(width, len(rows[i]))
+		}
+		for i, row := range rows { // Figlet assumes equal widths, pad sloppy fonts
+			for len(row) < width {
+				row = append(row, ' ')
+			}
+			rows[i] = row
+		}
+		return rows, true
+	}
+
+	for i := 0; i < flfRequiredChars; i++ {
+		rows, ok := glyph()
+		if !ok {
+			return f, nil
+		}
+		if i < 95 {
+			f.glyphs[rune(' '+i)] = rows
+		} else {
+			f.glyphs[flfDeutsch[i-95]] = rows
+		}
+	}
+	for scanner.Scan() { // Code tag line, e.g. "0x00C6  LATIN CAPITAL LETTER AE"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		code, err := strconv.ParseInt(fields[0], 0, 32)
+		if err != nil {
+			break // Figlet stops at the first line that isn't a code tag too
+		}
+		rows, ok := glyph()
+		if !ok {
+			break
+		}
+		if code >= 0 { // Negative codes aren't characters
+			f.glyphs[rune(code)] = rows
+		}
+	}
+	return f, scanner.Err()
+}
+
+// flfRunes decodes a font line, as Latin-1 if it isn't UTF-8 as in most old fonts.
+func flfRunes(line string) []rune {
+	if utf8.ValidString(line) {
+		return []rune(line)
+	}
+	runes := make([]rune, len(line))
+	for i := 0; i < len(line); i++ {
+		runes[i] = rune(line[i])
+	}
+	return runes
+}
+
+type loadedFIGfont struct {
+	modTime time.Time
+	font    *figFont
+}
+
+// figFonts keeps loaded fonts by path, reloaded when the file changes.
+var figFonts sync.Map
+
+// loadFIGfont loads the font at path, or returns it from figFonts.
+func loadFIGfont(path string) (*figFont, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if loaded, ok := figFonts.Load(path); ok && loaded.(loadedFIGfont).modTime.Equal(fi.ModTime()) {
+		return loaded.(loadedFIGfont).font, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	font, err := parseFIGfont(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	figFonts.Store(path, loadedFIGfont{fi.ModTime(), font})
+	return font, nil
+}
+
+// renderBuiltin renders text in the font at path at width, like `figlet -w`.
+// It stops when ctx is done, as figlet's process would be killed.
+func renderBuiltin(ctx context.Context, fontPath, text string, width int) (string, error) {
+	if control, ok := fontControlFiles.Load(fontPath); ok {
+		return "", fmt.Errorf("%s needs control file %s, which only figlet applies", fontPath, control)
+	}
+	font, err := loadFIGfont(fontPath)
+	if err != nil {
+		return "", err
+	}
+	return font.render(ctx, text, width)
+}
+
+// figRender is figlet's output state while rendering one text.
+type figRender struct {
+	font                *figFont
+	rtl                 bool     // Right-to-left font, letters are added on the left
+	width               int      // Output width, lines are at most width-1 long
+	rows                [][]rune // Line being built, one per glyph row
+	chars               []rune   // Characters on the line, to break it at a space
+	glyph               [][]rune // Glyph last added or tried
+	prevWidth, curWidth int      // Widths of the glyph before and of glyph
+	out                 strings.Builder
+}
+
+// render lays out text like figlet: a character that doesn't fit breaks the
+// line at the last space before it, or before it if the word is too long.
+// ctx is checked before each character.
+func (f *figFont) render(ctx context.Context, text string, width int) (string, error) {
+	width = max(width, 1) // As figlet does with -w below 1
+	r := &figRender{font: f, rtl: f.header.PrintDirection == 1, width: width, rows: make([][]rune, f.header.Height)}
+	wordBreak := 0 // Figlet's wordbreakmode: 0 line start, 1 in a word, 2 after a space, 3 in a later word, -1 skipping spaces after a break
+	for _, c := range text {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		switch {
+		case c == ' ' || c == '\t':
+			c = ' '
+		case c == '\n' || c == '\r' || c == '\v' || c == '\f':
+			c = '\n'
+		case c < ' ' || c == 0x7f:
+			continue
+		}
+		for retry := true; retry; {
+			retry = false
+			if wordBreak == -1 {
+				if c == ' ' {
+					break
+				}
+				wordBreak = 0
+				if c == '\n' {
+					break
+				}
+			}
+			switch {
+			case c == '\n':
+				r.printLine()
+				wordBreak = 0
+			case r.add(c):
+				switch {
+				case c != ' ' && wordBreak >= 2:
+					wordBreak = 3
+				case c != ' ':
+					wordBreak = 1
+				case wordBreak > 0:
+					wordBreak = 2
+				}
+			case len(r.rows[0]) == 0: // Wider than the line on its own, cut off
+				for _, row := range r.glyph {
+					if r.rtl && len(row) > r.width-1 {
+						row = row[len(row)-(r.width-1):]
+					}
+					r.putRow(row)
+				}
+				wordBreak = -1
+			case c == ' ':
+				r.breakLine(wordBreak == 2)
+				wordBreak = -1
+			default:
+				r.breakLine(wordBreak >= 2)
+				if wordBreak == 3 {
+					wordBreak = 1
+				} else {
+					wordBreak = 0
+				}
+				retry = true
+			}
+		}
+	}
+	if len(r.rows[0]) > 0 {
+		r.printLine()
+	}
+	return r.out.String(), nil
+}
+
+// glyphFor is c's glyph, or the font's glyph 0 for missing characters,
+// which is empty unless the font defines one.
+func (f *figFont) glyphFor(c rune) [][]rune {
+	if g, ok := f.glyphs[c]; ok {
+		return g
+	}
+	if g, ok := f.glyphs[0]; ok {
+		return g
+	}
+	return make([][]rune, f.header.Height)
+}
+
+// add appends c's glyph to the line, overlapping it as far as the layout
+// allows. Returns false if the line would get too long.
+func (r *figRender) add(c rune) bool {
+	r.glyph = r.font.glyphFor(c)
+	r.prevWidth, r.curWidth = r.curWidth, len(r.glyph[0])
+	amount := r.smushAmount()
+	if len(r.rows[0])+r.curWidth-amount > r.width-1 {
+		return false
+	}
+	for i, line := range r.rows {
+		g := r.glyph[i]
+		if r.rtl {
+			next := slices.Clone(g)
+			for k := 0; k < amount && k < len(line); k++ {
+				j := r.curWidth - amount + k
+				next[j] = r.smush(next[j], line[k])
+			}
+			r.rows[i] = append(next, line[min(amount, len(line)):]...)
+			continue
+		}
+		for k := 0; k < amount; k++ {
+			if j := len(line) - amount + k; j >= 0 {
+				line[j] = r.smush(line[j], g[k])
+			}
+		}
+		r.rows[i] = append(line, g[amount:]...)
+	}
+	r.chars = append(r.chars, c)
+	return true
+}
+
+// smushAmount is how many columns the glyph can overlap the line: the least,
+// over all rows, of the blank columns between them plus one where the
+// touching characters smush.
+func (r *figRender) smushAmount() int {
+	if r.font.layout&(smushSmush|smushKern) == 0 {
+		return 0
+	}
+	amount := r.curWidth
+	for i, line := range r.rows {
+		g := r.glyph[i]
+		left, right := line, g // The line's end meets the glyph's start
+		if r.rtl {
+			left, right = g, line
+		}
+		end := len(left)
+		for end > 0 && (end == len(left) || left[end] == ' ') {
+			end--
+		}
+		start := 0
+		for start < len(right) && right[start] == ' ' {
+			start++
+		}
+		var ch1, ch2 rune
+		if end < len(left) {
+			ch1 = left[end]
+		}
+		if start < len(right) {
+			ch2 = right[start]
+		}
+		n := start + len(left) - 1 - end
+		if ch1 == 0 || ch1 == ' ' {
+			n++
+		} else if ch2 != 0 && r.smush(ch1, ch2) != 0 {
+			n++
+		}
+		amount = min(amount, n)
+	}
+	return amount
+}
+
+// smush is the character lch and rch merge into, or 0 if they don't.
+func (r *figRender) smush(lch, rch rune) rune {
+	switch {
+	case lch == ' ':
+		return rch
+	case rch == ' ':
+		return lch
+	case r.prevWidth < 2 || r.curWidth < 2: // Figlet never merges into narrow glyphs
+		return 0
+	}
+	layout, hardblank := r.font.layout, r.font.header.Hardblank
+	if layout&smushSmush == 0 {
+		return 0
+	}
+	if layout&63 == 0 { // Universal smushing, the later letter wins
+		switch {
+		case lch == hardblank:
+			return rch
+		case rch == hardblank, r.rtl:
+			return lch
+		}
+		return rch
+	}
+	if layout&smushHardblank != 0 && lch == hardblank && rch == hardblank {
+		return lch
+	}
+	if lch == hardblank || rch == hardblank {
+		return 0
+	}
+	if layout&smushEqual != 0 && lch == rch {
+		return lch
+	}
+	in := func(c rune, set string) bool { return strings.ContainsRune(set, c) }
+	if layout&smushLowline != 0 {
+		if lch == '_' && in(rch, `|/\[]{}()<>`) {
+			return rch
+		}
+		if rch == '_' && in(lch, `|/\[]{}()<>`) {
+			return lch
+		}
+	}
+	if layout&smushHierarchy != 0 {
+		classes := []string{"|", `/\`, "[]", "{}", "()", "<>"}
+		for i, class := range classes[:len(classes)-1] {
+			later := strings.Join(classes[i+1:], "")
+			if in(lch, class) && in(rch, later) {
+				return rch
+			}
+			if in(rch, class) && in(lch, later) {
+				return lch
+			}
+		}
+	}
+	if layout&smushPair != 0 {
+		for _, pair := range []string{"[]", "{}", "()"} {
+			open, close := rune(pair[0]), rune(pair[1])
+			if lch == open && rch == close || lch == close && rch == open {
+				return '|'
+			}
+		}
+	}
+	if layout&smushBigX != 0 {
+		switch {
+		case lch == '/' && rch == '\\':
+			return '|'
+		case lch == '\\' && rch == '/':
+			return 'Y'
+		case lch == '>' && rch == '<':
+			return 'X'
+		}
+	}
+	return 0
+}
+
+// breakLine prints the line, at its last space if atSpace, carrying the
+// characters after it over to the next line.
+func (r *figRender) breakLine(atSpace bool) {
+	if !atSpace {
+		r.printLine()
+		return
+	}
+	chars := r.chars
+	end, space := len(chars)-1, -1
+	for ; end >= 0; end-- { // end is the last character before the last run of spaces
+		if space < 0 && chars[end] == ' ' {
+			space = end
+		}
+		if space >= 0 && chars[end] != ' ' {
+			break
+		}
+	}
+	if space < 0 { // No space on the line, figlet then carries nothing over
+		space = len(chars) - 1
+	}
+	before, after := slices.Clone(chars[:end+1]), slices.Clone(chars[space+1:])
+	r.clearLine()
+	for _, c := range before {
+		r.add(c)
+	}
+	r.printLine()
+	for _, c := range after {
+		r.add(c)
+	}
+}
+
+func (r *figRender) printLine() {
+	for _, row := range r.rows {
+		r.putRow(row)
+	}
+	r.clearLine()
+}
+
+func (r *figRender) clearLine() {
+	for i := range r.rows {
+		r.rows[i] = nil
+	}
+	r.chars = r.chars[:0]
+}
+
+// putRow writes one output row with hardblanks as spaces, right-aligned for
+// right-to-left fonts like figlet does (not when rendering unwrapped).
+func (r *figRender) putRow(row []rune) {
+	if r.width > 1 && len(row) > r.width-1 {
+		row = row[:r.width-1]
+	}
+	if r.rtl && r.width < unwrappedWidth {
+		r.out.WriteString(strings.Repeat(" ", max(r.width-1-len(row), 0)))
+	}
+	for _, c := range row {
+		if c == r.font.header.Hardblank {
+			c = ' '
+		}
+		r.out.WriteRune(c)
+	}
+	r.out.WriteByte('\n')
+}
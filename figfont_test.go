@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseFIGfontShortCommentBlock(t *testing.T) {
+	// The header promises more comment lines than the file has.
+	f, err := parseFIGfont(strings.NewReader("flf2a$ 1 1 1 0 5000\nonly comment\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.glyphs) != 0 {
+		t.Errorf("got %d glyphs, want none", len(f.glyphs))
+	}
+}
+
+func TestRenderStopsWithContext(t *testing.T) {
+	f, err := selftestFIGfont(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.render(ctx, "Hello", 80); err != context.Canceled {
+		t.Errorf("render with a canceled context = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Output Filters ---
+// Filters post-process the full figlet render before it is displayed or saved.
+
+type outputFilter struct {
+	Name  string
+	Apply func(string) string
+}
+
+var outputFilters = []outputFilter{
+	{Name: "none", Apply: func(s string) string { return s }},
+	{Name: "braille", Apply: brailleCompress},
+	{Name: "half-height", Apply: halfBlockCompress},
+	{Name: "metal", Apply: metalFilter},
+	{Name: "rainbow", Apply: rainbowFilter},
+}
+
+// findOutputFilter looks up a filter by name, nil if there's none.
+func findOutputFilter(name string) *outputFilter {
+	for i := range outputFilters {
+		if outputFilters[i].Name == name {
+			return &outputFilters[i]
+		}
+	}
+	return nil
+}
+
+// textGrid splits s into rows of terminal cells padded to the same width,
+// dropping trailing blank lines so they don't inflate the compressed output.
+// Wide characters (CJK, emoji) fill two cells and zero-width ones none, so
+// columns line up the way the render looks on screen.
+func textGrid(s string) [][]rune {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	width := 0
+	grid := make([][]rune, len(lines))
+	for i, line := range lines {
+		for _, r := range line {
+			for n := lipgloss.Width(string(r)); n > 0; n-- {
+				grid[i] = append(grid[i], r)
+			}
+		}
+		if len(grid[i]) > width {
+			width = len(grid[i])
+		}
+	}
+	for i, row := range grid {
+		for len(row) < width {
+			row = append(row, ' ')
+		}
+		grid[i] = row
+	}
+	return grid
+}
+
+// inked reports whether the cell at (row, col) is drawn, treating cells
+// outside the grid as blank.
+func inked(grid [][]rune, row, col int) bool {
+	if row >= len(grid) || col >= len(grid[row]) {
+		return false
+	}
+	return grid[row][col] != ' '
+}
+
+// Braille dot bits, indexed by [row][col] within a 2x4 cell.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// brailleCompress re-encodes s so that every 2x4 block of characters becomes
+// one braille pattern, halving the width and quartering the height.
+func brailleCompress(s string) string {
+	grid := textGrid(s)
+	if len(grid) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for row := 0; row < len(grid); row += 4 {
+		var line strings.Builder
+		for col := 0; col < len(grid[0]); col += 2 {
+			var pattern rune
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					if inked(grid, row+dy, col+dx) {
+						pattern |= brailleDots[dy][dx]
+					}
+				}
+			}
+			if pattern == 0 {
+				line.WriteRune(' ') // Blank braille (U+2800) renders inconsistently, use a plain space
+			} else {
+				line.WriteRune(0x2800 + pattern)
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// halfBlockCompress merges every two rows of s into one using the upper and
+// lower half-block characters, halving the height while keeping the width.
+func halfBlockCompress(s string) string {
+	grid := textGrid(s)
+	if len(grid) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for row := 0; row < len(grid); row += 2 {
+		var line strings.Builder
+		for col := 0; col < len(grid[0]); col++ {
+			top, bottom := inked(grid, row, col), inked(grid, row+1, col)
+			switch {
+			case top && bottom:
+				line.WriteRune('█')
+			case top:
+				line.WriteRune('▀')
+			case bottom:
+				line.WriteRune('▄')
+			default:
+				line.WriteRune(' ')
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ansiPattern matches CSI sequences (colors, cursor movement) and OSC sequences.
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// stripANSI removes terminal escape sequences, leaving plain text.
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
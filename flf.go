@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// --- FIGfont Headers ---
+// The first line of an .flf file, e.g. "flf2a$ 6 5 16 15 13 0 24463 229".
+
+type flfHeader struct {
+	Hardblank      rune `json:"hardblank"`
+	Height         int  `json:"height"`
+	Baseline       int  `json:"baseline"`
+	MaxLength      int  `json:"max_length"`
+	OldLayout      int  `json:"old_layout"`
+	CommentLines   int  `json:"comment_lines"`
+	PrintDirection int  `json:"print_direction"` // Optional, 0 (left-to-right) if absent
+	FullLayout     int  `json:"full_layout"`     // Optional, -1 if absent
+	CodetagCount   int  `json:"codetag_count"`   // Optional
+}
+
+// readFLFHeader reads and parses only the header line of the font at path.
+func readFLFHeader(path string) (flfHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return flfHeader{}, err
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return flfHeader{}, fmt.Errorf("%s: empty font file", path)
+	}
+	h, err := parseFLFHeader(line)
+	if err != nil {
+		return flfHeader{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return h, nil
+}
+
+// isFontFile reports whether name is a FIGlet (.flf) or TOIlet (.tlf) font.
+// TOIlet fonts are FIGfonts with a tlf2a signature and UTF-8 glyphs, which
+// figlet can't read but the built-in renderer draws (see isTOIletFont).
+func isFontFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".flf" || ext == ".tlf"
+}
+
+// isTOIletFont reports whether the font at path is a TOIlet font.
+func isTOIletFont(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".tlf")
+}
+
+// maxFLFHeight is far above any real font's height, which a damaged header
+// could otherwise make the renderer allocate rows for.
+const maxFLFHeight = 1000
+
+// maxFLFComments is far above any real font's comment block, which readers
+// skip line by line.
+const maxFLFComments = 10000
+
+func parseFLFHeader(line string) (flfHeader, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 6 || !(strings.HasPrefix(fields[0], "flf2a") || strings.HasPrefix(fields[0], "tlf2a")) || len(fields[0]) < 6 {
+		return flfHeader{}, fmt.Errorf("not a FIGfont (flf2a) or TOIlet (tlf2a) header")
+	}
+
+	h := flfHeader{FullLayout: -1}
+	h.Hardblank = []rune(fields[0][5:])[0]
+	ints := []*int{&h.Height, &h.Baseline, &h.MaxLength, &h.OldLayout, &h.CommentLines, &h.PrintDirection, &h.FullLayout, &h.CodetagCount}
+	for i, field := range fields[1:] {
+		if i >= len(ints) {
+			break
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return flfHeader{}, fmt.Errorf("invalid header field %q", field)
+		}
+		*ints[i] = n
+	}
+	if h.Height < 1 || h.Height > maxFLFHeight {
+		return flfHeader{}, fmt.Errorf("invalid font height %d", h.Height)
+	}
+	if h.CommentLines < 0 || h.CommentLines > maxFLFComments {
+		return flfHeader{}, fmt.Errorf("invalid comment line count %d", h.CommentLines)
+	}
+	return h, nil
+}
+
+// summary describes the header for people, e.g. in `fontlet preview`.
+func (h flfHeader) summary() string {
+	if h.Height == 0 {
+		return "unreadable header"
+	}
+	direction := "left-to-right"
+	if h.PrintDirection == 1 {
+		direction = "right-to-left"
+	}
+	return fmt.Sprintf("height %d, baseline %d, max line length %d, %s", h.Height, h.Baseline, h.MaxLength, direction)
+}
+
+// readFLFComments returns the comment block after the header, where font
+// authors put credits, dates and notes.
+func readFLFComments(path string, h flfHeader) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // Header
+	var lines []string
+	for len(lines) < h.CommentLines && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return strings.Join(lines, "\n"), scanner.Err()
+}
+
+// flfCredits is who made a font and the terms it's shared under, as far as
+// its comment block says. Fields are empty when the comments don't mention them.
+type flfCredits struct {
+	Author  string `json:"author,omitempty"`
+	License string `json:"license,omitempty"`
+}
+
+var (
+	authorField  = regexp.MustCompile(`(?i)^\s*(?:font\s+)?authors?(?:\(s\))?\s*[:=-]\s*(.+)`)
+	authorBy     = regexp.MustCompile(`(?i)^(?:\S+\s+)?(?:(?:made|written|created|designed|drawn|converted|done)\s+)?by\s+(.+)`) // "Big by ...", "Made by ..."
+	copyrightBy  = regexp.MustCompile(`(?i)(?:copyright|\(c\)|©)[\s:]*(?:\(c\)|©)?\s*(?:\d{4}(?:\s*[-,]\s*\d{4})*\s*)?(?:by\s+)?(.+)`)
+	trailingDate = regexp.MustCompile(`[\s,(]+\d{1,2}/\d{1,2}(?:/\d{2,4})?\)?$|[\s,(]+\d{1,2}/\d{2}\)?$|[\s,(]+(?:19|20)\d{2}\)?$`)
+	licenseTerms = regexp.MustCompile(`(?i)licen[cs]e|public domain|freeware|shareware|\bgpl\b|creative commons|\bcc[- ]by|rights reserved|permission|freely (?:copied|distributed|used)|distribut`)
+)
+
+// parseFLFCredits picks the author and license out of FLF comments, which
+// have no fixed format. The author comes from an "Author:" line, else a
+// leading "FONTNAME by NAME" or "Made by NAME", else the copyright line; the license is the first sentence
+// mentioning license terms.
+func parseFLFCredits(comments string) flfCredits {
+	var c flfCredits
+	lines := strings.Split(comments, "\n")
+	var byLine, copyrightLine string
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if m := authorField.FindStringSubmatch(line); m != nil && c.Author == "" {
+			c.Author = m[1]
+		}
+		if m := authorBy.FindStringSubmatch(line); m != nil && byLine == "" {
+			byLine = m[1]
+		}
+		if m := copyrightBy.FindStringSubmatch(line); m != nil && copyrightLine == "" {
+			copyrightLine = m[1]
+		}
+		if c.License == "" && licenseTerms.MatchString(line) {
+			sentence := line
+			for j := i + 1; j < len(lines) && j <= i+3 && !strings.HasSuffix(sentence, "."); j++ {
+				next := strings.TrimSpace(lines[j])
+				if next == "" {
+					break
+				}
+				sentence += " " + next
+			}
+			c.License = sentence
+		}
+	}
+	for _, candidate := range []string{byLine, copyrightLine} {
+		if c.Author == "" {
+			c.Author = candidate
+		}
+	}
+	if author, _, ok := strings.Cut(c.Author, " -- "); ok { // "Glenn Chappell 3/93 -- based on ..."
+		c.Author = author
+	}
+	c.Author = strings.TrimSpace(trailingDate.ReplaceAllString(strings.TrimSpace(c.Author), ""))
+	c.Author = truncate(strings.TrimRight(c.Author, ".,;:"), 60)
+	c.License = truncate(c.License, 200)
+	return c
+}
+
+// summary describes the credits on one line, e.g. for the highlight pane.
+func (c flfCredits) summary() string {
+	var parts []string
+	if c.Author != "" {
+		parts = append(parts, "by "+c.Author)
+	}
+	if c.License != "" {
+		parts = append(parts, c.License)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// flfCharset is how much of the character set a font actually draws.
+type flfCharset struct {
+	ASCII  int `json:"ascii"`  // Printable ASCII characters with a glyph, of 95
+	German int `json:"german"` // Of the 7 German characters every font has a slot for (ÄÖÜäöüß)
+	Extra  int `json:"extra"`  // Code-tagged characters beyond those, e.g. Latin-1 or box drawing
+}
+
+// flfRequiredChars is the number of glyphs every FIGfont starts with: ASCII 32-126, then ÄÖÜäöüß.
+const flfRequiredChars = 95 + 7
+
+// readFLFCharset counts the glyphs of the font at path that draw something.
+// Fonts often leave slots they don't support blank rather than omitting them.
+// A truncated font counts up to where it ends.
+func readFLFCharset(path string, h flfHeader) (flfCharset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return flfCharset{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for i := 0; i <= h.CommentLines; i++ { // Header and comments
+		if !scanner.Scan() {
+			return flfCharset{}, scanner.Err()
+		}
+	}
+	// glyph reads the next character's lines and reports whether they draw anything
+	glyph := func() (drawn, ok bool) {
+		for i := 0; i < h.Height; i++ {
+			if !scanner.Scan() {
+				return false, false
+			}
+			line := strings.TrimRight(scanner.Text(), "\r")
+			if line != "" {
+				line = strings.TrimRight(line, line[len(line)-1:]) // Endmarks, usually @
+			}
+			if strings.TrimSpace(strings.ReplaceAll(line, string(h.Hardblank), " ")) != "" {
+				drawn = true
+			}
+		}
+		return drawn, true
+	}
+
+	var cs flfCharset
+	for i := 0; i < flfRequiredChars; i++ {
+		drawn, ok := glyph()
+		if !ok {
+			return cs, scanner.Err()
+		}
+		switch {
+		case i == 0: // Space is blank by design
+			cs.ASCII++
+		case drawn && i < 95:
+			cs.ASCII++
+		case drawn:
+			cs.German++
+		}
+	}
+	for scanner.Scan() { // Code tag line, e.g. "0x00C6  LATIN CAPITAL LETTER AE"
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		drawn, ok := glyph()
+		if !ok {
+			break
+		}
+		if drawn {
+			cs.Extra++
+		}
+	}
+	return cs, scanner.Err()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFLFHeader(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    flfHeader
+		wantErr bool
+	}{
+		{line: "flf2a$ 6 5 16 15 11 0 24463 229", want: flfHeader{'$', 6, 5, 16, 15, 11, 0, 24463, 229}},
+		{line: "flf2a# 3 2 8 -1 0", want: flfHeader{'#', 3, 2, 8, -1, 0, 0, -1, 0}},
+		{line: "flf2a$ 4 3 10 0 2 1", want: flfHeader{'$', 4, 3, 10, 0, 2, 1, -1, 0}},
+		{line: "tlf2a$ 6 5 16 15 11", want: flfHeader{'$', 6, 5, 16, 15, 11, 0, -1, 0}},
+		{line: "flf2a 6 5 16 15 11", wantErr: true},
+		{line: "flf2a$ 6 5 16", wantErr: true},
+		{line: "flf2a$ six 5 16 15 11", wantErr: true},
+		{line: "flf2a$ 0 0 16 15 11", wantErr: true},
+		{line: "flf2a$ 100000 5 16 15 11", wantErr: true},
+		{line: "flf2a$ 1 1 1 0 -1", wantErr: true},
+		{line: "flf2a$ 1 1 1 0 9223372036854775807", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseFLFHeader(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFLFHeader(%q) error = %v, want error %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseFLFHeader(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestReadFLFCharsetShortCommentBlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "short.flf")
+	if err := os.WriteFile(path, []byte("flf2a$ 1 1 1 0 3\none comment\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A header read before comment counts were bounded, e.g. from the font index.
+	h := flfHeader{Hardblank: '$', Height: 1, CommentLines: 1 << 62}
+	cs, err := readFLFCharset(path, h)
+	if err != nil || cs != (flfCharset{}) {
+		t.Errorf("readFLFCharset = %+v, %v, want no glyphs and no error", cs, err)
+	}
+}
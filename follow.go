@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// --- Follow Mode ---
+// `tail -f build.log | fontlet --follow -f big` renders every line of stdin
+// as it arrives. On a terminal each render replaces the previous one, making
+// a live status display; redirected, the renders are simply appended.
+// Lines longer than maxFollowLine (minified JSON, progress bars redrawn
+// with \r) are cut there; nobody reads a banner that long anyway.
+
+const clearScreen = "\x1b[H\x1b[2J"
+
+const maxFollowLine = 4096
+
+func runFollow(stdin io.Reader, stdout *os.File, fontName string) error {
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	font, err := pickFontFuzzy(env.fonts, fontName, newRand(globalOptions.seed, globalOptions.seeded))
+	if err != nil {
+		return err
+	}
+	live := term.IsTerminal(stdout.Fd())
+
+	br := bufio.NewReader(stdin)
+	for {
+		raw, readErr := readLineTruncated(br, maxFollowLine)
+		if line := strings.TrimSpace(stripANSI(raw)); line != "" { // Build logs are often colored
+			output, err := runFiglet(env.figletCmdPath, font.Path, line, terminalWidth()) // Per line, so resizes apply
+			if err != nil {
+				return err
+			}
+			if live {
+				output = clearScreen + figletOutputStyle.Render(strings.TrimRight(output, "\n")) + "\n"
+			}
+			if _, err := io.WriteString(stdout, output); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// readLineTruncated reads a line of any length, keeping its first limit bytes
+// (cut back to whole UTF-8 characters).
+func readLineTruncated(br *bufio.Reader, limit int) (string, error) {
+	var line []byte
+	for {
+		chunk, more, err := br.ReadLine()
+		line = append(line, chunk[:min(len(chunk), limit-len(line))]...)
+		if err != nil || !more {
+			if len(line) == limit {
+				for len(line) > 0 && !utf8.Valid(line) {
+					line = line[:len(line)-1]
+				}
+			}
+			return string(line), err
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadLineTruncated(t *testing.T) {
+	long := strings.Repeat("x", 70_000) // Past bufio.Scanner's 64 KiB limit
+	tests := []struct {
+		input string
+		max   int
+		want  []string
+	}{
+		{"one\ntwo\n", 10, []string{"one", "two"}},
+		{"no newline", 10, []string{"no newline"}},
+		{"crlf\r\nend", 10, []string{"crlf", "end"}},
+		{"\n\nx\n", 10, []string{"", "", "x"}},
+		{long + "\nnext\n", 10, []string{"xxxxxxxxxx", "next"}},
+		{long + "\nnext\n", 100_000, []string{long, "next"}},
+		{"héé\n", 4, []string{"hé"}}, // é is two bytes, not cut in half
+	}
+	for _, tt := range tests {
+		br := bufio.NewReaderSize(strings.NewReader(tt.input), 16)
+		var got []string
+		for {
+			line, err := readLineTruncated(br, tt.max)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, line)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("lines of %.20q with max %d = %.60q, want %.60q", tt.input, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// --- Font Import ---
+// `fontlet fonts import ARCHIVE` unpacks the fonts of a .zip, .tar or
+// .tar.gz collection into the imported fonts dir, which discovery scans like
+// the packs. Each font is validated first; names that are already installed
+// (or appear twice in the archive) are skipped rather than overridden. TOIlet
+// fonts (.tlf) are imported too; the built-in renderer draws them.
+
+// importedFontsDir holds fonts added with `fontlet fonts import`.
+func importedFontsDir() (string, error) {
+	dir, err := packsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(dir), "fonts"), nil
+}
+
+type archiveFile struct {
+	name string // Path inside the archive
+	data []byte
+}
+
+// maxArchiveFonts bounds the fonts read from one archive, which are all held
+// in memory; the largest collections have a few hundred.
+const maxArchiveFonts = 10000
+
+var errTooManyFonts = fmt.Errorf("more than %d fonts in the archive", maxArchiveFonts)
+
+// readFontArchive returns the .flf and .tlf files of an archive, by extension.
+func readFontArchive(archivePath string) ([]archiveFile, error) {
+	lower := strings.ToLower(archivePath)
+	var files []archiveFile
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		zr, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || !isFontFile(f.Name) {
+				continue
+			}
+			if len(files) == maxArchiveFonts {
+				return nil, errTooManyFonts
+			}
+			if f.UncompressedSize64 > maxFontDownload {
+				return nil, fmt.Errorf("%s is over %d bytes", f.Name, maxFontDownload)
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := io.ReadAll(io.LimitReader(rc, maxFontDownload))
+			rc.Close()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			files = append(files, archiveFile{name: f.Name, data: data})
+		}
+	case strings.HasSuffix(lower, ".tar"), strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		var r io.Reader = f
+		if !strings.HasSuffix(lower, ".tar") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return nil, err
+			}
+			defer gz.Close()
+			r = gz
+		}
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if hdr.Typeflag != tar.TypeReg || !isFontFile(hdr.Name) {
+				continue
+			}
+			if len(files) == maxArchiveFonts {
+				return nil, errTooManyFonts
+			}
+			if hdr.Size > maxFontDownload {
+				return nil, fmt.Errorf("%s is over %d bytes", hdr.Name, maxFontDownload)
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", hdr.Name, err)
+			}
+			files = append(files, archiveFile{name: hdr.Name, data: data})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported archive %s, expected .zip, .tar, .tar.gz or .tgz", filepath.Base(archivePath))
+	}
+	return files, nil
+}
+
+// validateFontData checks that a font has the header of its kind, FIGfont
+// for .flf and TOIlet for .tlf, and isn't cut off before the printable ASCII
+// glyphs end.
+func validateFontData(name string, data []byte) error {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	signature := "flf2a"
+	if isTOIletFont(name) {
+		signature = "tlf2a"
+	}
+	if !bytes.HasPrefix(line, []byte(signature)) {
+		return fmt.Errorf("not a %s font, the header doesn't start with %s", strings.ToUpper(path.Ext(name)[1:]), signature)
+	}
+	h, err := parseFLFHeader(string(line))
+	if err != nil {
+		return err
+	}
+	// parseFLFHeader bounds Height and CommentLines, so this can't overflow
+	need := 1 + h.CommentLines + 95*h.Height
+	if lines := bytes.Count(data, []byte("\n")); lines < need {
+		return fmt.Errorf("truncated, %d lines where the ASCII characters alone need %d", lines, need)
+	}
+	return nil
+}
+
+func runFontsImport(args []string, stdout io.Writer) error {
+	fs := newFlagSet("fonts")
+	dir := fs.String("dir", "", "directory to import into (default ~/.local/share/fontlet/fonts)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("fonts import needs exactly one archive")
+	}
+	target := *dir
+	if target == "" {
+		var err error
+		if target, err = importedFontsDir(); err != nil {
+			return err
+		}
+	}
+	target = expandHome(target)
+
+	files, err := readFontArchive(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", fs.Arg(0), err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no .flf or .tlf fonts in %s", fs.Arg(0))
+	}
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]string) // Name -> where it is
+	for _, f := range env.fonts {
+		existing[f.Name] = shortenHome(f.Path)
+	}
+	if entries, err := os.ReadDir(target); err == nil { // Also fonts discovery doesn't list, e.g. hidden ones
+		for _, e := range entries {
+			if isFontFile(e.Name()) {
+				existing[strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))] = shortenHome(filepath.Join(target, e.Name()))
+			}
+		}
+	}
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+
+	imported, skipped, invalid := 0, 0, 0
+	for _, f := range files {
+		base := path.Base(f.name)
+		name := strings.TrimSuffix(base, path.Ext(base))
+		file := name + strings.ToLower(path.Ext(base))
+		if !validPackName(file) {
+			fmt.Fprintf(stdout, "invalid   %s: unusable file name\n", f.name)
+			invalid++
+			continue
+		}
+		if where, ok := existing[name]; ok {
+			fmt.Fprintf(stdout, "skipped   %s: already installed at %s\n", f.name, where)
+			skipped++
+			continue
+		}
+		if err := validateFontData(file, f.data); err != nil {
+			fmt.Fprintf(stdout, "invalid   %s: %v\n", f.name, err)
+			invalid++
+			continue
+		}
+		dest := filepath.Join(target, file)
+		if err := writeAtomic(dest, 0644, func(w *os.File) error { // Discovery never sees half a font
+			_, err := w.Write(f.data)
+			return err
+		}); err != nil {
+			return err
+		}
+		existing[name] = shortenHome(dest)
+		fmt.Fprintf(stdout, "imported  %s\n", f.name)
+		imported++
+	}
+	fmt.Fprintf(stdout, "Imported %d fonts into %s (%d skipped as duplicates, %d invalid)\n", imported, shortenHome(target), skipped, invalid)
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testFontData is a font of one-line glyphs with the given header signature.
+func testFontData(signature string, glyphs int) []byte {
+	return []byte(signature + "$ 1 1 2 0 0\n" + strings.Repeat("x@\n", glyphs))
+}
+
+func TestValidateFontData(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"ok.flf", testFontData("flf2a", 102), ""},
+		{"ok.tlf", testFontData("tlf2a", 102), ""},
+		{"ascii-only.flf", testFontData("flf2a", 95), ""},
+		{"swapped.flf", testFontData("tlf2a", 102), "not a FLF font"},
+		{"swapped.tlf", testFontData("flf2a", 102), "not a TLF font"},
+		{"short.flf", testFontData("flf2a", 94), "truncated"},
+		{"empty.flf", nil, "not a FLF font"},
+		{"huge-comments.flf", []byte("flf2a$ 1 1 1 0 9223372036854775807\n"), "invalid comment line count"},
+		{"huge-height.flf", []byte("flf2a$ 9223372036854775807 1 1 0 0\n"), "invalid font height"},
+	}
+	for _, tt := range tests {
+		err := validateFontData(tt.name, tt.data)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("validateFontData(%s) = %v, want no error", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("validateFontData(%s) = %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func writeTestZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(data))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTestTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(data))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadFontArchive(t *testing.T) {
+	files := map[string]string{
+		"fonts/a.flf":    "A",
+		"fonts/B.TLF":    "B",
+		"fonts/c.flc":    "control file",
+		"README":         "not a font",
+		"deep/er/d.flf":  "D",
+		"fonts/e.flf.gz": "compressed",
+	}
+	want := []string{"B.TLF", "a.flf", "d.flf"}
+	dir := t.TempDir()
+	for _, archive := range []string{"fonts.zip", "fonts.tar.gz"} {
+		path := filepath.Join(dir, archive)
+		if strings.HasSuffix(archive, ".zip") {
+			writeTestZip(t, path, files)
+		} else {
+			writeTestTarGz(t, path, files)
+		}
+		got, err := readFontArchive(path)
+		if err != nil {
+			t.Fatalf("%s: %v", archive, err)
+		}
+		var names []string
+		for _, f := range got {
+			names = append(names, filepath.Base(f.name))
+		}
+		slices.Sort(names)
+		if strings.Join(names, " ") != strings.Join(want, " ") {
+			t.Errorf("%s: read %v, want %v", archive, names, want)
+		}
+	}
+
+	if _, err := readFontArchive(filepath.Join(dir, "fonts.rar")); err == nil || !strings.Contains(err.Error(), "unsupported archive") {
+		t.Errorf("reading a .rar = %v, want an unsupported archive error", err)
+	}
+}
+
+func TestReadFontArchiveTooManyFonts(t *testing.T) {
+	files := make(map[string]string, maxArchiveFonts+1)
+	for i := 0; i <= maxArchiveFonts; i++ {
+		files["f"+strconv.Itoa(i)+".flf"] = ""
+	}
+	path := filepath.Join(t.TempDir(), "many.zip")
+	writeTestZip(t, path, files)
+	if _, err := readFontArchive(path); !errors.Is(err, errTooManyFonts) {
+		t.Errorf("readFontArchive = %v, want %v", err, errTooManyFonts)
+	}
+}
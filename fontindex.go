@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- Font Index ---
+// Discovery results are cached per directory in the user cache dir. A directory
+// is only re-read when its mtime changed (a font was added, removed or renamed),
+// so warm starts cost one stat per directory instead of a walk and a header read
+// per font. In-place edits don't touch the directory mtime; ctrl+r does a full scan.
+
+const fontIndexVersion = 4 // Bump when the index format or what it lists changes
+
+type fontIndex struct {
+	Version int                   `json:"version"`
+	Dirs    map[string]indexedDir `json:"dirs"`
+}
+
+type indexedDir struct {
+	ModTime time.Time     `json:"mod_time"`
+	Fonts   []indexedFont `json:"fonts"`
+	Subdirs []string      `json:"subdirs"`
+}
+
+type indexedFont struct {
+	Path    string     `json:"path"`
+	ModTime time.Time  `json:"mod_time"`
+	Header  flfHeader  `json:"header"`
+	Credits flfCredits `json:"credits"`
+	Control string     `json:"control,omitempty"` // Control file the font needs, see controlfile.go
+}
+
+func newFontIndex() *fontIndex {
+	return &fontIndex{Version: fontIndexVersion, Dirs: make(map[string]indexedDir)}
+}
+
+func fontIndexPath() (string, error) {
+	if noState {
+		return "", errNoState
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "fontlet", "fonts.json"), nil
+}
+
+// loadFontIndex returns the cached index, or an empty one if it's missing,
+// unreadable or from another format version.
+func loadFontIndex() *fontIndex {
+	path, err := fontIndexPath()
+	if err != nil {
+		return newFontIndex()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return newFontIndex()
+	}
+	var idx fontIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Version != fontIndexVersion || idx.Dirs == nil {
+		return newFontIndex()
+	}
+	return &idx
+}
+
+// save writes the index. It's only a cache, so callers may ignore the error.
+func (idx *fontIndex) save() error {
+	path, err := fontIndexPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, 0644, func(f *os.File) error { // Another fontlet may be reading it
+		_, err := f.Write(data)
+		return err
+	})
+}
+
+// scan returns the fonts under dir (recursively, in lexical order like
+// filepath.WalkDir), reusing cached entries of unchanged directories. Entries
+// seen are copied into next, so directories that no longer exist drop out.
+func (idx *fontIndex) scan(dir string, next *fontIndex) ([]indexedFont, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := idx.Dirs[dir]
+	if !ok || !entry.ModTime.Equal(info.ModTime()) {
+		if entry, err = readFontDir(dir, info.ModTime()); err != nil {
+			return nil, err
+		}
+	}
+	next.Dirs[dir] = entry
+
+	fonts := append([]indexedFont(nil), entry.Fonts...)
+	for _, sub := range entry.Subdirs {
+		subFonts, err := idx.scan(sub, next)
+		if err != nil {
+			return nil, err
+		}
+		fonts = append(fonts, subFonts...)
+	}
+	return fonts, nil
+}
+
+// readFontDir lists one directory, reading the header of every font in it.
+func readFontDir(dir string, modTime time.Time) (indexedDir, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return indexedDir{}, err
+	}
+	entry := indexedDir{ModTime: modTime}
+	for _, d := range entries {
+		path := filepath.Join(dir, d.Name())
+		if d.IsDir() {
+			entry.Subdirs = append(entry.Subdirs, path)
+			continue
+		}
+		if !isFontFile(d.Name()) {
+			continue
+		}
+		font := indexedFont{Path: path}
+		if info, err := d.Info(); err == nil {
+			font.ModTime = info.ModTime()
+		}
+		var err error
+		if font.Header, err = readFLFHeader(path); err == nil { // Unparseable fonts are still listed, figlet reports the error
+			comments, _ := readFLFComments(path, font.Header)
+			font.Credits = parseFLFCredits(comments)
+			font.Control = controlFileName(strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())), comments)
+		}
+		entry.Fonts = append(entry.Fonts, font)
+	}
+	return entry, nil
+}
@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"io/fs"
+	"math/rand/v2"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -28,35 +35,68 @@ const (
 
 // --- Styles ---
 var (
-	docStyle             = lipgloss.NewStyle().Margin(1, 2)
-	titleStyle           = lipgloss.NewStyle().Foreground(lipgloss.Color("62")).Bold(true).MarginBottom(1)
-	helpStyle            = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).MarginTop(1)
-	errorStyle           = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
-	successStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("76")).Bold(true) // Green for success
-	figletOutputStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("69"))             // Purple for figlet output
-	listTitleStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Bold(true).Padding(0, 0, 0, 0).MarginBottom(1)
-	inputPromptStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("7")).Bold(true)
-	inputValueStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
-	statusMessageStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Padding(1, 0) // Orange for status/choices
+	docStyle             = renderer.NewStyle().Margin(1, 2)
+	titleStyle           = renderer.NewStyle().Foreground(lipgloss.Color("62")).Bold(true).MarginBottom(1)
+	helpStyle            = renderer.NewStyle().Foreground(lipgloss.Color("241")).MarginTop(1)
+	errorStyle           = renderer.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	successStyle         = renderer.NewStyle().Foreground(lipgloss.Color("76")).Bold(true) // Green for success
+	figletOutputStyle    = renderer.NewStyle().Foreground(lipgloss.Color("69"))             // Purple for figlet output
+	listTitleStyle       = renderer.NewStyle().Foreground(lipgloss.Color("229")).Bold(true).Padding(0, 0, 0, 0).MarginBottom(1)
+	inputPromptStyle     = renderer.NewStyle().Foreground(lipgloss.Color("7")).Bold(true)
+	inputValueStyle      = renderer.NewStyle().Foreground(lipgloss.Color("15"))
+	statusMessageStyle   = renderer.NewStyle().Foreground(lipgloss.Color("214")).Padding(1, 0) // Orange for status/choices
 
 	// For custom list item delegate
-	itemStyle         = lipgloss.NewStyle().PaddingLeft(2)
-	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(0).Foreground(lipgloss.Color("208")) // Orange for selected item
-	fontNameStyle     = lipgloss.NewStyle().Bold(true)
+	itemStyle         = renderer.NewStyle().PaddingLeft(2)
+	selectedItemStyle = renderer.NewStyle().PaddingLeft(0).Foreground(lipgloss.Color("208")) // Orange for selected item
+	fontNameStyle     = renderer.NewStyle().Bold(true)
+	sourceStyle       = renderer.NewStyle().Foreground(lipgloss.Color("241")) // Dim grey for font directory
+	spinnerStyle      = renderer.NewStyle().Foreground(lipgloss.Color("205"))
 )
 
+// applyTheme sets the style colors from the config theme, using the defaults
+// above for empty fields. It runs at startup and whenever the config is reloaded.
+func applyTheme(t themeConfig) {
+	color := func(c, def string) lipgloss.Color {
+		if c == "" {
+			return lipgloss.Color(def)
+		}
+		return lipgloss.Color(c)
+	}
+	titleStyle = titleStyle.Foreground(color(t.Title, "62"))
+	helpStyle = helpStyle.Foreground(color(t.Help, "241"))
+	errorStyle = errorStyle.Foreground(color(t.Error, "196"))
+	successStyle = successStyle.Foreground(color(t.Success, "76"))
+	figletOutputStyle = figletOutputStyle.Foreground(color(t.Output, "69"))
+	selectedItemStyle = selectedItemStyle.Foreground(color(t.Selected, "208"))
+	statusMessageStyle = statusMessageStyle.Foreground(color(t.Status, "214"))
+	spinnerStyle = spinnerStyle.Foreground(color(t.Spinner, "205"))
+}
+
 // --- Application States ---
 type appState int
 
 const (
-	stateInitialLoading appState = iota // Checking figlet, initial font scan
+	stateInitialLoading appState = iota // Text entered before the background font scan finished
 	stateInputText
-	stateLoadingPreviews // After text input, generating previews for all fonts
 	stateSelectFontWithPreview
+	stateShowcase             // One font at a time, full size
 	stateGeneratingFullOutput // After font selection, generating the full output
-	stateOutputChoice         // (t)erminal or (f)ile?
+	stateOutputChoice         // Menu of output targets (terminal, file)
 	stateSaveFileNameInput
+	stateConfirmSystemWrite // Confirm writing a preset to a system file like /etc/issue
+	stateConfirmOverwrite   // The target file exists with different content
+	stateConfirmMkdir       // The target's directory doesn't exist yet
+	stateOverwriteDiff      // Diff of the existing file against the pending save
+	stateSaving             // Writing the file, with progress
+	stateWidthWarning       // Output is wider than the configured limit, re-render or save anyway?
+	stateSnippetPicker
+	stateSnippetName // Naming the current text before adding it as a snippet
+	stateHistoryPicker
+	stateTransformPicker // Choosing a transform like leetspeak for the text, see transforms.go
 	stateDisplayFiglet
+	stateCompose           // Arranging renders into one document, see composer.go
+	stateConfirmQuit       // Quitting with an unsaved render
 	stateShowStatusMessage // For brief messages like "Saved!"
 	stateError
 )
@@ -77,135 +117,664 @@ type model struct {
 	errorMessage     string
 	statusMessage    string   // For temporary messages like "Saved!" or choices
 	figletCmdPath    string
+	filterIndex      int // Index into outputFilters, applied to fullFigletOutput
+	exportIndex      int // Index into exportFormats, chosen when saving
+	pendingSave      pendingSave // Export awaiting confirmation in stateConfirmSystemWrite or stateConfirmOverwrite
+	diffViewport     viewport.Model
+	saving           *saveJob // Save in progress in stateSaving
+	previewProgress  *previewProgress // Previews streaming into the list, nil once all are in
+	previewed        previewTarget    // What the stored previews show, to skip rendering the same again
+	previews         *previewStore    // In-list previews by font path, shared with the list delegate
+	busySince        time.Time        // Start of the preview run or save in progress, for the completion bell
+	saveOpts         saveOptions // Toggles applied when writing files
+	renderWidth      int         // Width override for the full render, 0 follows the terminal
+	widthRenders     map[int]string // Width -> full render at that width, for flipping widths in the output view
+	editingText      bool            // Inline text editor open over the screen, see edittext.go
+	editInput        textinput.Model // The inline editor's input
+	errorState       appState              // Where the error on the error screen struck
+	errorRetry       func(m model) tea.Cmd // Runs the failed operation again, see errorscreen.go
+	errorFonts       bool                  // Font discovery failed, so the error screen offers a font directory
+	errorFontDir     bool                  // Typing a font directory on the error screen
+	fontDirInput     textinput.Model
+	diagnostics      []doctorCheck // Shown on the error screen, nil while hidden
+	resumeSave       bool        // Return to the filename screen once the narrower re-render finishes
+	cfg              config
+	configModTime    time.Time // Last seen config mtime, polled to hot-reload changes
+	keys             keyMap
+	fontsLoaded      bool // Background font scan finished
+	recallPending    bool // A history entry was recalled before the scan finished, see selectRecalledFont
+	highlightSeq     int               // Debounce counter for render-on-highlight mode
+	highlightRenders map[string]string // Font path -> render of the current text, highlight mode only
+	showcaseRenders  map[string]string // Font path -> full-width render of the current text
+	snippetList      list.Model
+	inputDraft       string // Text typed before opening the snippet picker
+	history          []historyEntry // Recent full renders, newest first
+	historyList      list.Model
+	outputMenu       list.Model
+	transformMenu    list.Model
+	transformIndex   int // Index into textTransforms, applied to inputText when rendering
+	rng              *rand.Rand // Random font picks, seeded by --seed for reproducible sessions
+	actions          []loggedAction // Session log of renders and saves
+	showLog          bool           // Session log pane toggled on
+	unsaved          bool     // The full render was never saved
+	quitReturnState  appState // Where to go back to when quitting is cancelled
+	notice           string // Transient message shown under the title, e.g. config reload results
+	noticeID         int    // Lets a stale timeout leave a newer notice alone
+	pickOnly         bool         // Choosing a font ends the session, see `fontlet exec`
+	pickedFont       fontMetadata // The font chosen in pickOnly mode
+	insertMode       bool         // The first full render ends the session, see --insert
+	printOnExit      bool         // Print the last full render to stdout after quitting, see printexit.go
+	usage            usageStats   // Renders and saves per font, see usage.go
+	sortByUsage      bool         // Font list sorted most used first instead of by name
+	composition      composition  // Blocks added with "Compose" in the output menu
+	composed         bool         // fullFigletOutput is the composition, not a single render
+}
+
+type pendingSave struct {
+	path    string
+	content string
+}
+
+// outputText is the full render with the active output filter applied.
+func (m model) outputText() string {
+	return outputFilters[m.filterIndex].Apply(m.fullFigletOutput)
+}
+
+// outputChoicePrompt is shown in stateOutputChoice, including the active filter.
+func (m model) outputChoicePrompt() string {
+	return fmt.Sprintf("Where should the output go? [filter: %s]", outputFilters[m.filterIndex].Name)
+}
+
+// savedBanner is the output as it should be written to disk: with the colors
+// of a color filter (metal, rainbow) when colors are kept, else plain text.
+// The theme's color is the TUI's own and never saved.
+func (m model) savedBanner() string {
+	if m.saveOpts.Colors {
+		return m.outputText()
+	}
+	return stripANSI(m.outputText())
+}
+
+// exportedOutput is the saved banner wrapped in the chosen export format,
+// with the line ending and encoding options applied.
+func (m model) exportedOutput() (string, error) {
+	f := exportFormats[m.exportIndex]
+	if f.Script != nil && m.composed {
+		return "", errComposedScript
+	}
+	if f.Script != nil {
+		if err := m.scriptUnsupported(); err != nil {
+			return "", err
+		}
+		return f.Script(m.renderSpec()), nil
+	}
+	banner, err := m.withFooter(m.savedBanner())
+	if err != nil {
+		return "", err
+	}
+	if m.saveOpts.Center && m.cfg.CenterWidth > 0 {
+		banner = centerBlock(banner, m.cfg.CenterWidth)
+	}
+	content, err := f.Export(banner, m.cfg)
+	if err != nil || f.Binary {
+		return content, err
+	}
+	return m.saveOpts.encode(content), nil
+}
+
+// saveOptionsView summarizes the export format and save toggles.
+func (m model) saveOptionsView() string {
+	onOff := func(b bool) string {
+		if b {
+			return "on"
+		}
+		return "off"
+	}
+	lineEnding := "LF"
+	if m.saveOpts.CRLF {
+		lineEnding = "CRLF"
+	}
+	view := fmt.Sprintf("Format: %s • Colors: %s • Line endings: %s • BOM: %s",
+		exportFormats[m.exportIndex].Name, onOff(m.saveOpts.Colors), lineEnding, onOff(m.saveOpts.BOM))
+	if m.cfg.Footer.Text != "" {
+		view += " • Footer: " + onOff(m.saveOpts.Footer)
+	}
+	if m.cfg.CenterWidth > 0 {
+		view += fmt.Sprintf(" • Centered in %d columns: %s", m.cfg.CenterWidth, onOff(m.saveOpts.Center))
+	}
+	return view
+}
+
+// defaultSavePath is the system file targeted by the chosen export preset, if any.
+func (m model) defaultSavePath() string {
+	if f := exportFormats[m.exportIndex]; f.DefaultPath != nil {
+		return f.DefaultPath(m.cfg)
+	}
+	return ""
+}
+
+// filenamePlaceholder suggests a filename matching the chosen export format.
+func (m model) filenamePlaceholder() string {
+	return fmt.Sprintf("Enter filename (e.g., banner%s)", exportFormats[m.exportIndex].Extension)
 }
 
 type fontMetadata struct {
 	Name          string // e.g., "standard"
 	Path          string // e.g., "/usr/share/figlet/standard.flf"
-	PreviewRender string // Truncated figlet output for list display
+	Dir           string   // Directory the font was found in
+	Shadows       []string // Same-named fonts in lower-precedence directories, overridden by this one
+	ModTime       time.Time // Used to detect changed fonts when rescanning
+	Header        flfHeader // Parsed FLF header, zero if the font couldn't be parsed
+	Credits       flfCredits // Author and license from the FLF comments
+	Aliases       []string   // Names given to the font in font_aliases
+	Control       string     // Control file the font needs, e.g. "tsalagi.flc"
+	ControlPath   string     // Where the control file was found, empty if it's missing
+}
+
+// sourceAnnotation describes where the font came from and which same-named
+// fonts it overrides.
+func (fm fontMetadata) sourceAnnotation() string {
+	annotation := shortenHome(fm.Dir)
+	if len(fm.Aliases) > 0 {
+		annotation = fmt.Sprintf("aka %s  %s", strings.Join(fm.Aliases, ", "), annotation)
+	}
+	if len(fm.Shadows) > 0 {
+		overridden := make([]string, len(fm.Shadows))
+		for i, p := range fm.Shadows {
+			overridden[i] = shortenHome(filepath.Dir(p))
+		}
+		annotation += fmt.Sprintf(" (overrides %s)", strings.Join(overridden, ", "))
+	}
+	return annotation + fm.controlAnnotation()
 }
 
 // For list.Item interface
 func (fm fontMetadata) Title() string       { return fm.Name } // Used for filtering
-func (fm fontMetadata) Description() string { return fm.PreviewRender } // Not directly used by default delegate
-func (fm fontMetadata) FilterValue() string { return fm.Name }
+func (fm fontMetadata) Description() string { return fm.sourceAnnotation() } // Previews are in the model's previewStore
+func (fm fontMetadata) FilterValue() string { return strings.Join(append([]string{fm.Name}, fm.Aliases...), " ") }
 
 
 // --- Messages ---
 type initialResourcesLoadedMsg struct{ fonts []fontMetadata } // Fonts without previews initially
-type previewsGeneratedMsg struct{ fontsWithPreviews []fontMetadata }
+// previewTarget is what previews show: the text, rendered at a width.
+type previewTarget struct {
+	text  string
+	width int
+}
 type fullFigletRenderedMsg struct{ output string }
 type fileSavedMsg struct { path string }
-type errorMsg struct{ err error }
+type sudoWriteNeededMsg struct{ pendingSave } // Permission denied, retry through sudo
+type fontEditedMsg struct{ font fontMetadata }   // Editor exited, font needs rescanning
+type fontRefreshedMsg struct {
+	font    fontMetadata
+	preview string
+}
+type configTickMsg struct{} // Time to check the config for changes
+type configReloadedMsg struct {
+	cfg     config
+	modTime time.Time
+	err     error
+}
+type noticeTimeoutMsg struct{ id int }
+type fontsRescannedMsg struct {
+	fonts                   []fontMetadata
+	added, removed, changed int
+	stale                   []string // Paths of the fonts whose previews no longer hold
+}
 type statusTimeoutMsg struct{} // To clear status messages
 
 
-func initialModel() model {
-	// Figlet check
-	cmdPath, err := exec.LookPath("figlet")
-	if err != nil {
-		return model{
-			state:        stateError,
-			errorMessage: "figlet command not found. Please install figlet to use this script.",
-		}
-	}
+const textPlaceholder = "Enter text to figletize..."
+
+// tuiOptions are the command-line options that affect the interactive session.
+type tuiOptions struct {
+	seed     uint64
+	seeded   bool   // --seed was given
+	text     string // Start with this text instead of asking for it
+	pickOnly bool   // Quit as soon as a font is chosen, see `fontlet exec`
+	insert   bool   // Quit with the full render as soon as it's done, see --insert
+	print    bool   // Print the last full render on exit, see --print-on-exit
+}
 
+// globalOptions are set by main from the global flags, for subcommands that
+// start the TUI themselves.
+var globalOptions tuiOptions
+
+func initialModel(opts tuiOptions) model {
 	ti := textinput.New()
-	ti.Placeholder = "Enter text to figletize..."
+	ti.Placeholder = textPlaceholder
 	ti.Focus()
-	ti.CharLimit = 256
 	ti.Width = 50
 	ti.PromptStyle = inputPromptStyle
 	ti.TextStyle = inputValueStyle
 
+	cfg, err := loadConfig()
+	if err != nil {
+		return model{
+			state:        stateError,
+			errorMessage: err.Error(),
+		}
+	}
+	cmdPath, err := lookupFiglet(cfg)
+	if err != nil {
+		return model{
+			state:        stateError,
+			errorMessage: err.Error(),
+		}
+	}
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
 
-	return model{
-		state:         stateInitialLoading,
+	m := model{
+		state:         stateInputText, // Fonts are scanned in the background while the user types
 		textInput:     ti,
 		spinner:       s,
 		figletCmdPath: cmdPath,
+		configModTime: configModTime(),
+		rng:           newRand(opts.seed, opts.seeded),
+		previews:      newPreviewStore(cfg.previewMemory()),
+		pickOnly:      opts.pickOnly,
+		insertMode:    opts.insert,
+		printOnExit:   opts.print,
+	}
+	if opts.text != "" {
+		opts.text = joinPastedLines(opts.text, cfg.PasteJoin) // Piped text can have several lines
+		m.inputText = opts.text
+		m.textInput.SetValue(opts.text)
+		m.textInput.Blur()
+		m.state = stateInitialLoading // Previews start once the scan finishes
+	}
+	m, err = m.withConfig(cfg)
+	if err != nil {
+		return model{
+			state:        stateError,
+			errorMessage: err.Error(),
+		}
+	}
+	if m.history, err = loadHistory(); err != nil { // Not fatal, the next render starts a new one
+		m.notice = errorStyle.Render(err.Error())
 	}
+	if m.usage, err = loadUsage(); err != nil { // Not fatal either, counting restarts
+		m.notice = errorStyle.Render(err.Error())
+	}
+	return m
+}
+
+// withConfig applies a (re)loaded config: keybindings, theme, and settings.
+// Styles captured by components are refreshed so theme changes show up live.
+func (m model) withConfig(cfg config) (model, error) {
+	keys, err := newKeyMap(cfg.Keys)
+	if err != nil {
+		return m, err
+	}
+	cmdPath, err := lookupFiglet(cfg)
+	if err != nil {
+		return m, err
+	}
+	applyTheme(cfg.Theme)
+	if cfg.Footer.Text != m.cfg.Footer.Text { // Newly configured footers start on, a toggle survives other reloads
+		m.saveOpts.Footer = cfg.Footer.Text != ""
+	}
+	if cfg.CenterWidth != m.cfg.CenterWidth { // Likewise for centering
+		m.saveOpts.Center = cfg.CenterWidth > 0
+	}
+	m.cfg = cfg
+	m.keys = keys
+	if m.previews != nil {
+		m.previews.setBudget(cfg.previewMemory())
+	}
+	m.figletCmdPath = cmdPath // From the next render on
+	if m.fonts != nil { // Aliases may have changed
+		m.fonts = applyFontAliases(m.fonts, cfg.FontAliases)
+		if m.fontList.Items() != nil {
+			m.fontList.SetItems(m.fontListItems())
+		}
+	}
+	m.textInput.CharLimit = cfg.CharLimit
+	m.spinner.Style = spinnerStyle
+	m.figletViewport.Style = figletOutputStyle
+	if m.fontList.Items() != nil { // Check if list is initialized
+		m.fontList.SetDelegate(newItemDelegate(m.delegatePreviewLines(), m.previews))
+		m.fontList.Styles.Title = listTitleStyle
+		m.fontList.Styles.HelpStyle = helpStyle.MarginTop(0)
+		m.fontList.Styles.StatusBar = statusMessageStyle.Padding(0, 1)
+	}
+	return m, nil
 }
 
 func (m model) Init() tea.Cmd {
 	if m.state == stateError {
 		return tea.Quit // Quit immediately if figlet not found
 	}
-	return tea.Batch(m.spinner.Tick, m.loadInitialFontsCmd())
+	if m.state == stateInitialLoading { // Text given on the command line
+		return tea.Batch(m.spinner.Tick, m.loadInitialFontsCmd(), watchConfigCmd())
+	}
+	return tea.Batch(textinput.Blink, m.loadInitialFontsCmd(), watchConfigCmd())
+}
+
+// watchConfigInterval is how often the config file's mtime is polled.
+const watchConfigInterval = 2 * time.Second
+
+func watchConfigCmd() tea.Cmd {
+	return tea.Tick(watchConfigInterval, func(time.Time) tea.Msg { return configTickMsg{} })
+}
+
+// checkConfigCmd reloads the config if it changed since the last load, or
+// unconditionally when forced by the reload key.
+func (m model) checkConfigCmd(force bool) tea.Cmd {
+	last := m.configModTime
+	return func() tea.Msg {
+		modTime := configModTime()
+		if !force && modTime.Equal(last) {
+			return nil
+		}
+		cfg, err := loadConfig()
+		return configReloadedMsg{cfg: cfg, modTime: modTime, err: err}
+	}
+}
+
+// showNotice sets the transient notice and returns the command that clears it.
+func (m *model) showNotice(text string) tea.Cmd {
+	m.noticeID++
+	id := m.noticeID
+	m.notice = text
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg { return noticeTimeoutMsg{id} })
 }
 
 // --- Commands ---
 func (m model) loadInitialFontsCmd() tea.Cmd {
 	return func() tea.Msg {
-		fonts, err := findFigletFonts() // This just gets names and paths
+		fonts, err := findFigletFonts(m.cfg.FontDirs, false) // This just gets names and paths
 		if err != nil {
-			return errorMsg{err}
+			return errorMsg{err: err, retry: model.loadInitialFontsCmd, fonts: true}
 		}
-		return initialResourcesLoadedMsg{fonts}
+		return initialResourcesLoadedMsg{applyFontAliases(fonts, m.cfg.FontAliases)}
+	}
+}
+
+// streamPreviews renders every font's preview in the background, several
+// at a time, sending each to the list as it finishes (see progress.go).
+// Fonts without a preview show a placeholder meanwhile, others keep theirs.
+func (m model) streamPreviews() (model, tea.Cmd) {
+	if m.previewProgress != nil {
+		m.previewProgress.cancel() // Superseded, its previews would be dropped
+	}
+	ctx, cancel := context.WithCancel(shutdownCtx)
+	p := &previewProgress{total: len(m.fonts), target: previewTarget{m.renderText(), previewWidth(m.termWidth)}, results: make(chan previewRenderedMsg, len(m.fonts)), cancel: cancel}
+	fonts := append([]fontMetadata(nil), m.fonts...) // Snapshot, rendered concurrently
+	go func() {
+		defer cancel()
+		cache := openPreviewCache(m.cfg)
+		var hits atomic.Int64
+		parallelFor(len(fonts), func(i int) {
+			if ctx.Err() != nil {
+				return
+			}
+			preview, hit := m.loadPreview(cache, fonts[i], p.target)
+			if hit {
+				hits.Add(1)
+			}
+			p.done.Add(1)
+			p.results <- previewRenderedMsg{p, fonts[i].Path, preview}
+		})
+		cache.recordLookups(int(hits.Load()), int(p.done.Load()-hits.Load())) // Fonts skipped once canceled weren't looked up
+		close(p.results)
+	}()
+	m.previewProgress = p
+	m.busySince = time.Now()
+	m.fontList.Title = m.fontListTitle()
+	return m, p.next()
+}
+
+// parallelFor calls fn with 0 to n-1 from a pool of one goroutine per CPU,
+// so renders running figlet overlap, and returns when all calls have.
+func parallelFor(n int, fn func(i int)) {
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < n; i = int(next.Add(1) - 1) {
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// loadPreview reads the preview of target for font from the cache,
+// reporting a hit, or renders it and caches the render for next time.
+func (m model) loadPreview(cache previewCache, font fontMetadata, target previewTarget) (string, bool) {
+	key := previewKey(font, target.text, target.width, m.figletCmdPath)
+	if output, ok := cache.get(key); ok {
+		return truncateString(output, previewLines), true
+	}
+	output, err := runFiglet(m.figletCmdPath, font.Path, target.text, target.width)
+	if err != nil {
+		return fmt.Sprintf("Error rendering: %v", err), false
 	}
+	cache.put(key, output) // Best effort, a read-only cache only costs the next session renders
+	return truncateString(output, previewLines), false
 }
 
-func (m model) generatePreviewsCmd() tea.Cmd {
+// renderPreview renders the in-list preview of target for one font.
+func (m model) renderPreview(font fontMetadata, target previewTarget) string {
+	// Figlet's -w is in characters, not pixels.
+	output, err := runFiglet(m.figletCmdPath, font.Path, target.text, target.width)
+	if err != nil {
+		// Store error or a placeholder in preview
+		return fmt.Sprintf("Error rendering: %v", err)
+	}
+	return truncateString(output, previewLines)
+}
+
+// editFontCmd suspends the TUI and opens the font file in $VISUAL or $EDITOR.
+func (m model) editFontCmd(font fontMetadata) tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := append(strings.Fields(editor), font.Path) // $EDITOR may carry flags, e.g. "code -w"
+	cmd := exec.Command(args[0], args[1:]...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("editor failed for %s: %w", font.Path, err), retry: func(m model) tea.Cmd { return m.editFontCmd(font) }}
+		}
+		return fontEditedMsg{font}
+	})
+}
+
+// rescanFontsCmd re-runs font discovery, noting the fonts that are new or
+// changed since previous was scanned; their previews render once they're on
+// screen (see reloadEvictedPreviews).
+func (m model) rescanFontsCmd(previous []fontMetadata) tea.Cmd {
 	return func() tea.Msg {
-		fontsWithPreviews := make([]fontMetadata, len(m.fonts))
-		// Determine a reasonable preview width, slightly less than terminal width
-		// Figlet's -w is in characters, not pixels.
-		// Subtracting some for list padding and scrollbar.
-		previewRenderWidth := m.termWidth - 20 
-		if previewRenderWidth < 20 {
-			previewRenderWidth = 20 // Minimum sensible width
-		}
-
-		for i, font := range m.fonts {
-			output, err := runFiglet(m.figletCmdPath, font.Path, m.inputText, previewRenderWidth)
-			if err != nil {
-				// Store error or a placeholder in preview
-				font.PreviewRender = fmt.Sprintf("Error rendering: %v", err)
-			} else {
-				font.PreviewRender = truncateString(output, previewLines)
+		fonts, err := findFigletFonts(m.cfg.FontDirs, true)
+		if err != nil {
+			return errorMsg{err: err, retry: func(m model) tea.Cmd { return m.rescanFontsCmd(previous) }, fonts: true}
+		}
+		fonts = applyFontAliases(fonts, m.cfg.FontAliases)
+
+		known := make(map[string]fontMetadata, len(previous))
+		for _, f := range previous {
+			known[f.Path] = f
+		}
+		var msg fontsRescannedMsg
+		for _, font := range fonts {
+			old, ok := known[font.Path]
+			switch {
+			case !ok:
+				msg.added++
+				msg.stale = append(msg.stale, font.Path)
+			case !old.ModTime.Equal(font.ModTime):
+				msg.changed++
+				msg.stale = append(msg.stale, font.Path)
 			}
-			fontsWithPreviews[i] = font
+			delete(known, font.Path)
+		}
+		msg.removed = len(known)
+		for path := range known {
+			msg.stale = append(msg.stale, path)
 		}
-		return previewsGeneratedMsg{fontsWithPreviews}
+		msg.fonts = fonts
+		return msg
 	}
 }
 
-func (m model) renderFullFigletCmd(fontPath, text string) tea.Cmd {
+// refreshFontCmd re-renders the preview of a single font, e.g. after editing it.
+func (m model) refreshFontCmd(font fontMetadata) tea.Cmd {
+	target := previewTarget{m.renderText(), previewWidth(m.termWidth)}
 	return func() tea.Msg {
-		// For full output, use a generous width or terminal width
-		// Subtract a bit for docStyle margins
-		renderWidth := m.termWidth - docStyle.GetHorizontalFrameSize() - 4 
-		if renderWidth < 20 { renderWidth = 20 }
+		return fontRefreshedMsg{font, m.renderPreview(font, target)}
+	}
+}
+
+// fullRenderWidth is the figlet width of the full output.
+func (m model) fullRenderWidth() int {
+	// For full output, use a generous width or terminal width
+	// Subtract a bit for docStyle margins
+	renderWidth := m.termWidth - docStyle.GetHorizontalFrameSize() - 4 
+	if renderWidth < 20 { renderWidth = 20 }
+	if m.renderWidth > 0 {
+		renderWidth = m.renderWidth // Narrower re-render requested at save time, or a width preset
+	}
+	return renderWidth
+}
 
+func (m model) renderFullFigletCmd(fontPath, text string) tea.Cmd {
+	renderWidth := m.fullRenderWidth()
+	return func() tea.Msg {
 		output, err := runFiglet(m.figletCmdPath, fontPath, text, renderWidth)
 		if err != nil {
-			return errorMsg{fmt.Errorf("failed to run figlet for full output: %w", err)}
+			return errorMsg{err: fmt.Errorf("failed to run figlet for full output: %w", err), retry: func(m model) tea.Cmd { return m.renderFullFigletCmd(fontPath, text) }}
 		}
 		return fullFigletRenderedMsg{output}
 	}
 }
 
-func (m model) saveToFileCmd(filename, content string) tea.Cmd {
-    return func() tea.Msg {
-        err := os.WriteFile(filename, []byte(content), 0644)
-        if err != nil {
-            return errorMsg{fmt.Errorf("failed to save file '%s': %w", filename, err)}
-        }
-        return fileSavedMsg{path: filename}
-    }
+// writeSystemFileCmd writes a confirmed preset export, asking for sudo when
+// the file or its directory isn't writable by the current user.
+func (m model) writeSystemFileCmd(p pendingSave) tea.Cmd {
+	return func() tea.Msg {
+		err := writeAtomic(p.path, 0644, func(f *os.File) error {
+			_, err := f.WriteString(p.content)
+			return err
+		})
+		if errors.Is(err, fs.ErrPermission) {
+			return sudoWriteNeededMsg{p}
+		}
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to save file '%s': %w", p.path, err), retry: func(m model) tea.Cmd { return m.writeSystemFileCmd(p) }}
+		}
+		return fileSavedMsg{path: p.path}
+	}
 }
 
+// sudoWriteCmd suspends the TUI so sudo can prompt for a password, then pipes
+// the content through `sudo tee`.
+func (m model) sudoWriteCmd(p pendingSave) tea.Cmd {
+	cmd := exec.Command("sudo", "tee", p.path)
+	cmd.Stdin = strings.NewReader(p.content)
+	cmd.Stdout = io.Discard
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to save file '%s' with sudo: %w", p.path, err), retry: func(m model) tea.Cmd { return m.sudoWriteCmd(p) }}
+		}
+		return fileSavedMsg{path: p.path}
+	})
+}
+
+// isPresetTarget reports whether path is the system file the chosen export
+// preset writes to, wherever it's configured. Those saves are confirmed first
+// and go through sudo when the file or its directory isn't writable.
+func (m model) isPresetTarget(path string) bool {
+	target := m.defaultSavePath()
+	return target != "" && filepath.Clean(path) == filepath.Clean(target)
+}
 
 // --- Helper Functions ---
-func findFigletFonts() ([]fontMetadata, error) {
-	// (This function is largely the same as before, just ensuring it returns fontMetadata without previews yet)
-	var fontDir string
-	var fontPaths []string
+// findFigletFonts scans the configured extra directories, installed font packs
+// and then the system font directory. The first font found with a given name wins, so a customized
+// copy in an extra directory overrides the system font; the overridden paths are
+// recorded so the list can surface the collision. Unchanged directories are
+// served from the font index unless fullScan is set.
+func findFigletFonts(extraDirs []string, fullScan bool) ([]fontMetadata, error) {
+	var fontDirs []string
+	for _, dir := range extraDirs {
+		dir = expandHome(dir)
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			fontDirs = append(fontDirs, dir)
+		}
+	}
+	if dir, err := packsDir(); err == nil { // Installed with `fontlet packs install`
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			fontDirs = append(fontDirs, dir)
+		}
+	}
+	if dir, err := importedFontsDir(); err == nil { // Added with `fontlet fonts import`
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			fontDirs = append(fontDirs, dir)
+		}
+	}
+	if dir := systemFontDir(); dir != "" {
+		fontDirs = append(fontDirs, dir)
+	}
+	if len(fontDirs) == 0 { return nil, fmt.Errorf("could not find a font directory: install figlet's fonts, add \"font_dirs\" to the config or install a font pack with `fontlet packs install`") }
+
+	idx := newFontIndex()
+	if !fullScan {
+		idx = loadFontIndex()
+	}
+	next := newFontIndex()
 
+	var fonts []fontMetadata
+	byName := make(map[string]int) // Font name -> index in fonts
+	hidden := hiddenFonts()        // Removed with `fontlet fonts remove`
+	for _, fontDir := range fontDirs {
+		found, err := idx.scan(fontDir, next)
+		if err != nil { return nil, fmt.Errorf("error walking font directory %s: %w", fontDir, err) }
+
+		for _, f := range found {
+			if hidden[f.Path] {
+				continue
+			}
+			nameWithExt := filepath.Base(f.Path)
+			name := strings.TrimSuffix(nameWithExt, filepath.Ext(nameWithExt))
+			if i, ok := byName[name]; ok {
+				fonts[i].Shadows = append(fonts[i].Shadows, f.Path)
+				continue
+			}
+			byName[name] = len(fonts)
+			font := fontMetadata{Name: name, Path: f.Path, Dir: filepath.Dir(f.Path), ModTime: f.ModTime, Header: f.Header, Credits: f.Credits, Control: f.Control}
+			if font.Control != "" {
+				if font.ControlPath = findControlFile(font.Control, font.Path, fontDirs); font.ControlPath != "" {
+					fontControlFiles.Store(font.Path, font.ControlPath)
+				}
+			}
+			fonts = append(fonts, font)
+		}
+	}
+	_ = next.save() // Best effort, a missing index only slows down the next start
+	if len(fonts) == 0 { return nil, fmt.Errorf("no .flf or .tlf font files found in %s or subdirectories", strings.Join(fontDirs, ", ")) }
+
+	sort.Slice(fonts, func(i, j int) bool { return fonts[i].Name < fonts[j].Name })
+	return fonts, nil
+}
+
+// systemFontDir asks figlet for its default font directory, falling back to
+// common install locations. Returns "" if none exist.
+func systemFontDir() string {
+	if useFakeFiglet {
+		dir, _ := fakeFontDir()
+		return dir
+	}
+	var fontDir string
 	cmd := exec.Command("figlet", "-I", "2")
 	output, err := cmd.Output()
 	if err == nil {
@@ -227,42 +796,95 @@ func findFigletFonts() ([]fontMetadata, error) {
 			}
 		}
 	}
-	if fontDir == "" { return nil, fmt.Errorf("could not find figlet font directory") }
+	return fontDir
+}
 
-	err = filepath.WalkDir(fontDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil { return err }
-		if !d.IsDir() && strings.HasSuffix(strings.ToLower(d.Name()), ".flf") {
-			fontPaths = append(fontPaths, path)
+// expandHome replaces a leading "~" with the user's home directory.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
 		}
-		return nil
-	})
-	if err != nil { return nil, fmt.Errorf("error walking font directory %s: %w", fontDir, err) }
-	if len(fontPaths) == 0 { return nil, fmt.Errorf("no .flf font files found in %s or subdirectories", fontDir) }
+	}
+	return path
+}
 
-	var fonts []fontMetadata
-	for _, p := range fontPaths {
-		nameWithExt := filepath.Base(p)
-		name := strings.TrimSuffix(nameWithExt, filepath.Ext(nameWithExt))
-		fonts = append(fonts, fontMetadata{Name: name, Path: p}) // PreviewRender is empty initially
+// shortenHome is the inverse of expandHome, for display.
+func shortenHome(path string) string {
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if path == home {
+			return "~"
+		}
+		if strings.HasPrefix(path, home+string(filepath.Separator)) {
+			return "~" + path[len(home):]
+		}
 	}
-	sort.Slice(fonts, func(i, j int) bool { return fonts[i].Name < fonts[j].Name })
-	return fonts, nil
+	return path
 }
 
 func runFiglet(figletCmdPath, fontPath, text string, width int) (string, error) {
-	cmd := exec.Command(figletCmdPath, "-f", fontPath, "-w", fmt.Sprintf("%d", width), text)
-	output, err := cmd.Output()
-	if err != nil {
+	return runFigletContext(shutdownCtx, figletCmdPath, fontPath, text, width)
+}
+
+// runFigletContext is runFiglet with figlet killed when ctx is done, e.g. to
+// bound render time in serve mode.
+func runFigletContext(ctx context.Context, figletCmdPath, fontPath, text string, width int) (string, error) {
+	switch {
+	case figletCmdPath == fakeFigletCmd:
+		return renderFake(fontPath, text, width)
+	case figletCmdPath == builtinFigletCmd || isTOIletFont(fontPath):
+		output, err := renderBuiltin(ctx, fontPath, text, width)
+		if err == nil {
+			return output, nil
+		}
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("render stopped (path: %s, width: %d): %w", fontPath, width, ctx.Err())
+		}
+		if isTOIletFont(fontPath) {
+			return "", err // figlet can't read TOIlet fonts either
+		}
+		cmdPath, lookErr := exec.LookPath("figlet") // Fonts the built-in renderer can't draw, if figlet is installed
+		if lookErr != nil {
+			return "", err
+		}
+		figletCmdPath = cmdPath
+	}
+	args := []string{"-f", fontPath}
+	if control, ok := fontControlFiles.Load(fontPath); ok {
+		args = append(args, "-C", control.(string))
+	}
+	cmd := exec.CommandContext(ctx, figletCmdPath, append(args, "-w", fmt.Sprintf("%d", width), "--", text)...) // Text like "-I2" isn't an option
+	output, err := runChild(cmd)
+	if err != nil && ctx.Err() == nil {
 		// Try without -w if it failed (some figlet versions/fonts might not like it or small widths)
-		cmd = exec.Command(figletCmdPath, "-f", fontPath, text)
-		output, err = cmd.Output()
-		if err != nil {
-		    return "", fmt.Errorf("figlet failed (path: %s, text: %s, width: %d): %w", fontPath, text, width, err)
+		cmd = exec.CommandContext(ctx, figletCmdPath, append(args, "--", text)...)
+		output, err = runChild(cmd)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("figlet stopped (path: %s, width: %d): %w", fontPath, width, ctx.Err())
 		}
+		return "", fmt.Errorf("figlet failed (path: %s, text: %s, width: %d): %w", fontPath, text, width, err)
 	}
 	return string(output), nil
 }
 
+// joinPastedLines makes multi-line pasted text fit the single-line input:
+// lines are trimmed, blank ones dropped and the rest joined with sep.
+func joinPastedLines(s, sep string) string {
+	if !strings.ContainsAny(s, "\r\n") {
+		return s
+	}
+	s = strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n") // Terminals often paste line breaks as CR
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, sep)
+}
+
 func truncateString(s string, maxLines int) string {
 	lines := strings.Split(s, "\n")
 	if len(lines) > maxLines {
@@ -280,6 +902,7 @@ func truncateString(s string, maxLines int) string {
 type itemDelegate struct {
 	Styles           *delegateStyles
 	PreviewLines int // Max lines for preview
+	Previews     *previewStore
 }
 
 type delegateStyles struct {
@@ -288,9 +911,11 @@ type delegateStyles struct {
 	NormalPreview lipgloss.Style
 	SelectedPreview lipgloss.Style
 	FontName lipgloss.Style
+	Source   lipgloss.Style // Font directory annotation
 }
 
-func newItemDelegate() *itemDelegate {
+// newItemDelegate renders each font with up to lines of preview; 0 lists names only.
+func newItemDelegate(lines int, previews *previewStore) *itemDelegate {
 	// Define styles for the delegate here
 	// These will be used in the Render method
 	return &itemDelegate{
@@ -298,19 +923,29 @@ func newItemDelegate() *itemDelegate {
 			NormalTitle:   itemStyle.Height(1), // Base style for the item line
 			SelectedTitle: selectedItemStyle.Height(1),
 			NormalPreview: itemStyle.Faint(true),
-			SelectedPreview: selectedItemStyle.Foreground(lipgloss.Color("208")).Faint(false), // Selected preview less faint
+			SelectedPreview: selectedItemStyle.Faint(false), // Selected preview less faint
 			FontName: fontNameStyle,
+			Source:   sourceStyle,
 		},
-		PreviewLines: previewLines,
+		PreviewLines: lines,
+		Previews:     previews,
 	}
 }
 
 func (d *itemDelegate) Height() int {
+	if d.PreviewLines == 0 {
+		return 1 // Names only
+	}
 	// Height for font name + preview lines + 1 for spacing or ensure enough space
 	return 1 + d.PreviewLines + 1
 }
 
-func (d *itemDelegate) Spacing() int { return 1 }
+func (d *itemDelegate) Spacing() int {
+	if d.PreviewLines == 0 {
+		return 0
+	}
+	return 1
+}
 
 func (d *itemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
 
@@ -323,14 +958,27 @@ func (d *itemDelegate) Render(w io.Writer, m list.Model, index int, listItem lis
 	var styledName, styledPreview string
 	isSelected := index == m.Index()
 
-	nameStr := d.Styles.FontName.Render(item.Name)
+	if d.PreviewLines == 0 { // Names only, the source is shown in the preview pane
+		if isSelected {
+			fmt.Fprint(w, d.Styles.SelectedTitle.Render("➤ "+d.Styles.FontName.Render(item.Name)))
+		} else {
+			fmt.Fprint(w, d.Styles.NormalTitle.Render("  "+d.Styles.FontName.Render(item.Name)))
+		}
+		return
+	}
+
+	nameStr := d.Styles.FontName.Render(item.Name) + "  " + d.Styles.Source.Render(item.sourceAnnotation())
+	preview, ok := d.Previews.get(item.Path)
+	if !ok { // Not rendered yet, or evicted and being loaded again
+		preview = pendingPreview
+	}
 
 	if isSelected {
 		styledName = d.Styles.SelectedTitle.Render("➤ " + nameStr)
-		styledPreview = d.Styles.SelectedPreview.Render(item.PreviewRender)
+		styledPreview = d.Styles.SelectedPreview.Render(preview)
 	} else {
 		styledName = d.Styles.NormalTitle.Render("  " + nameStr)
-		styledPreview = d.Styles.NormalPreview.Render(item.PreviewRender)
+		styledPreview = d.Styles.NormalPreview.Render(preview)
 	}
 	
 	// Ensure preview doesn't overflow delegate height by truncating it again (should be pre-truncated)
@@ -339,9 +987,11 @@ func (d *itemDelegate) Render(w io.Writer, m list.Model, index int, listItem lis
 	if len(previewLinesRender) > d.PreviewLines {
 		previewLinesRender = previewLinesRender[:d.PreviewLines]
 	}
+	// Clip by display width, not bytes or runes, so wide characters can't wrap
+	// a line and push the rest of the list down
+	clip := renderer.NewStyle().MaxWidth(m.Width())
 
-
-	fmt.Fprintf(w, "%s\n%s", styledName, strings.Join(previewLinesRender, "\n"))
+	fmt.Fprintf(w, "%s\n%s", clip.Render(styledName), clip.Render(strings.Join(previewLinesRender, "\n")))
 }
 
 
@@ -362,14 +1012,52 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		listHeight := m.termHeight - headerHeight - footerHeight - 2 // Adjusted for margins
 
 		if m.fontList.Items() != nil { // Check if list is initialized
-    		m.fontList.SetSize(msg.Width-h, listHeight)
+    		m.fontList.SetSize(m.fontListWidth(), listHeight)
+		}
+		if m.snippetList.Items() != nil {
+			m.snippetList.SetSize(msg.Width-h, listHeight)
+		}
+		if m.historyList.Items() != nil {
+			m.historyList.SetSize(msg.Width-h, listHeight)
+		}
+		if m.outputMenu.Items() != nil {
+			m.outputMenu.SetSize(msg.Width-h, listHeight-2) // Prompt line
+		}
+		if m.transformMenu.Items() != nil {
+			m.transformMenu.SetSize(msg.Width-h, listHeight)
 		}
 		m.figletViewport.Width = msg.Width - h
 		m.figletViewport.Height = listHeight
+		m.diffViewport.Width = msg.Width - h
+		m.diffViewport.Height = listHeight - 2 // Title line
+		m.showcaseRenders = nil // Rendered for the old width
+		if m.state == stateShowcase {
+			cmds = append(cmds, m.ensureShowcaseRender())
+		}
 
 
+	case list.FilterMatchesMsg: // Results of the filter typed into the active list
+		var cmd tea.Cmd
+		switch m.state {
+		case stateSnippetPicker:
+			m.snippetList, cmd = m.snippetList.Update(msg)
+		case stateHistoryPicker:
+			m.historyList, cmd = m.historyList.Update(msg)
+		default:
+			m.fontList, cmd = m.fontList.Update(msg)
+		}
+		cmds = append(cmds, cmd)
+
+	case tea.ResumeMsg:
+		// Bubble Tea restores the alt screen and repaints, but not mouse
+		// reporting, and the terminal may have been resized meanwhile
+		cmds = append(cmds, tea.EnableMouseCellMotion, tea.WindowSize())
+		if m.textInput.Focused() {
+			cmds = append(cmds, textinput.Blink)
+		}
+
 	case spinner.TickMsg:
-		if m.state == stateInitialLoading || m.state == stateLoadingPreviews || m.state == stateGeneratingFullOutput {
+		if m.state == stateInitialLoading || m.state == stateGeneratingFullOutput || m.state == stateSaving {
 			var cmd tea.Cmd
 			m.spinner, cmd = m.spinner.Update(msg)
 			cmds = append(cmds, cmd)
@@ -377,66 +1065,291 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	
 	case initialResourcesLoadedMsg:
 		m.fonts = msg.fonts // Fonts without previews yet
-		m.state = stateInputText
-		m.textInput.Focus() // Focus input after initial load
+		m.previewed = previewTarget{}
+		m.previews.clear()
+		m.fontsLoaded = true
+		if m.recallPending {
+			var cmd tea.Cmd
+			m, cmd = m.selectRecalledFont()
+			cmds = append(cmds, cmd)
+		} else if m.state == stateInitialLoading { // Text was entered while scanning
+			var cmd tea.Cmd
+			m, cmd = m.startPreviews()
+			cmds = append(cmds, cmd)
+		}
 
-	case previewsGeneratedMsg:
-		m.fonts = msg.fontsWithPreviews // Now fonts have previews
-		items := make([]list.Item, len(m.fonts))
-		for i, f := range m.fonts {
-			items[i] = f
+	case previewRenderedMsg:
+		if msg.progress != m.previewProgress { // Superseded by newer text
+			break
 		}
-		
-		delegate := newItemDelegate()
-		listHeight := m.termHeight - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView()) -2
-		newList := list.New(items, delegate, m.termWidth-docStyle.GetHorizontalFrameSize(), listHeight)
-		newList.Title = "Available Fonts (with Previews)"
-		newList.Styles.Title = listTitleStyle
-		newList.Styles.HelpStyle = helpStyle.MarginTop(0) // Adjust help style margin for list
-		newList.SetShowStatusBar(true) // Show item count, etc.
-		newList.SetFilteringEnabled(true)
-		newList.Styles.StatusBar = statusMessageStyle.Padding(0,1)
-
-
-		m.fontList = newList
-		m.state = stateSelectFontWithPreview
+		m = m.setPreview(msg.path, msg.preview)
+		cmds = append(cmds, msg.progress.next())
+
+	case previewsReloadedMsg:
+		m.storeReloadedPreviews(msg)
+
+	case previewsDoneMsg:
+		if msg.progress != m.previewProgress {
+			break
+		}
+		m.previewed = msg.progress.target
+		m.previewProgress = nil
+		m.fontList.Title = m.fontListTitle()
+		cmds = append(cmds, m.bellIfSlow())
+
+	case highlightDebounceMsg:
+		if msg.seq == m.highlightSeq && m.state == stateSelectFontWithPreview {
+			if font, ok := m.highlightedFont(); ok {
+				if _, done := m.highlightRenders[font.Path]; !done {
+					cmds = append(cmds, m.renderHighlightCmd(font))
+				}
+			}
+		}
+
+	case showcaseRenderedMsg:
+		if m.showcaseRenders == nil {
+			m.showcaseRenders = make(map[string]string)
+		}
+		m.showcaseRenders[msg.path] = msg.output
+
+	case highlightRenderedMsg:
+		if msg.target != (previewTarget{m.renderText(), m.previewPaneWidth()}) { // Started before an edit or resize
+			if font, ok := m.highlightedFont(); ok && font.Path == msg.path && m.state == stateSelectFontWithPreview {
+				cmds = append(cmds, m.renderHighlightCmd(font))
+			}
+			break
+		}
+		if m.highlightRenders == nil { // Mode switched on by a config reload
+			m.highlightRenders = make(map[string]string)
+		}
+		m.highlightRenders[msg.path] = msg.output
 	
 	case fullFigletRenderedMsg:
 		m.fullFigletOutput = msg.output
-		m.state = stateOutputChoice
-		m.statusMessage = "Output to (t)erminal or save to (f)ile?"
+		m.composed = false
+		m.widthRenders = nil
+		m.unsaved = true
+		m.logAction("Rendered %q in %s", m.renderText(), m.selectedFontMeta.Name)
+		if m.insertMode { // main prints it for the editor once the TUI is gone
+			m.unsaved = false
+			return m, tea.Sequence(tea.Batch(m.recordHistory(), m.recordUsage(false)), tea.Quit)
+		}
+		cmds = append(cmds, m.recordHistory(), m.recordUsage(false))
+		if m.resumeSave { // Re-rendered to fit the width limit, continue saving
+			m.resumeSave = false
+			m.state = stateSaveFileNameInput
+			m.statusMessage = ""
+			m.textInput.Focus()
+			break
+		}
+		if m.state == stateDisplayFiglet { // Re-rendered for edited text
+			m.figletViewport.SetContent(m.outputText())
+			break
+		}
+		m = m.toOutputChoice()
+
+	case historySavedMsg:
+		if msg.err != nil {
+			cmds = append(cmds, m.showNotice(errorStyle.Render(msg.err.Error())))
+		}
+
+	case usageSavedMsg:
+		if msg.err != nil {
+			cmds = append(cmds, m.showNotice(errorStyle.Render(msg.err.Error())))
+		}
+
+	case autoFitMsg:
+		if m.state == stateSelectFontWithPreview {
+			var cmd tea.Cmd
+			m, cmd = m.selectAutoFit(msg)
+			cmds = append(cmds, cmd)
+		}
 
+	case widthRenderedMsg:
+		if m.widthRenders != nil && msg.fontPath == m.selectedFontMeta.Path && msg.text == m.renderText() {
+			m.widthRenders[msg.width] = msg.output
+			if msg.width == m.fullRenderWidth() {
+				m = m.showWidthRender(msg.output)
+			}
+		}
+
+	case saveProgressMsg:
+		if m.state == stateSaving && msg.job == m.saving {
+			cmds = append(cmds, saveProgressTick(msg.job))
+		}
+
+	case saveCanceledMsg:
+		m.saving = nil
+		m.state = stateSaveFileNameInput
+		m.statusMessage = errorStyle.Render(fmt.Sprintf("Save canceled, %s was left unchanged.", msg.path))
+		cmds = append(cmds, m.textInput.Focus())
 
 	case fileSavedMsg:
+		m.saving = nil
+		m.unsaved = false
+		cmds = append(cmds, m.bellIfSlow())
+		m.logAction("Saved %s (%s, %s) to %s", m.selectedFontMeta.Name, exportFormats[m.exportIndex].Name, outputFilters[m.filterIndex].Name, msg.path)
+		cmds = append(cmds, m.recordUsage(true))
 		m.statusMessage = successStyle.Render(fmt.Sprintf("Saved to %s!", msg.path))
+		if limit := exportFormats[m.exportIndex].MessageLimit; limit > 0 {
+			if n := len(chatMessages(m.outputText(), limit)); n > 1 {
+				m.statusMessage += "\n" + statusMessageStyle.Padding(0).Render(fmt.Sprintf("The banner is over the %d character message limit, so it was split into %d code blocks to paste one by one.", limit, n))
+			}
+		}
 		m.state = stateShowStatusMessage
 		// Return to font selection after a brief moment
 		cmds = append(cmds, tea.Tick(time.Second*2, func(t time.Time) tea.Msg { return statusTimeoutMsg{} }))
 	
+	case fontEditedMsg:
+		cmds = append(cmds, m.refreshFontCmd(msg.font))
+
+	case fontRefreshedMsg:
+		delete(m.showcaseRenders, msg.font.Path)
+		if !m.highlightMode() {
+			m.previews.put(msg.font.Path, msg.preview)
+		}
+		if m.state == stateShowcase {
+			cmds = append(cmds, m.ensureShowcaseRender())
+		}
+		if m.highlightMode() {
+			delete(m.highlightRenders, msg.font.Path)
+			if font, ok := m.highlightedFont(); ok && font.Path == msg.font.Path {
+				cmds = append(cmds, m.renderHighlightCmd(font))
+			}
+		}
+		for i, f := range m.fonts {
+			if f.Path == msg.font.Path {
+				m.fonts[i] = msg.font
+			}
+		}
+		for i, item := range m.fontList.Items() {
+			if f, ok := item.(fontMetadata); ok && f.Path == msg.font.Path {
+				cmds = append(cmds, m.fontList.SetItem(i, msg.font))
+			}
+		}
+
+	case clipboardCopiedMsg:
+		if msg.err == nil {
+			m.unsaved = false // Copied is as good as saved
+		}
+		cmds = append(cmds, m.showNotice(msg.notice()))
+
+	case previewCachePurgedMsg:
+		status := fmt.Sprintf("Purged the preview cache: %d previews, %s", msg.entries, formatBytes(msg.size))
+		if msg.err != nil {
+			status = errorStyle.Render(fmt.Sprintf("Failed to purge the preview cache: %v", msg.err))
+		}
+		cmds = append(cmds, m.fontList.NewStatusMessage(status))
+
+	case fontsRescannedMsg:
+		m.fonts = msg.fonts
+		for _, path := range msg.stale {
+			m.previews.forget(path)
+		}
+		cmds = append(cmds,
+			m.fontList.SetItems(m.fontListItems()),
+			m.fontList.NewStatusMessage(fmt.Sprintf("Rescanned: %d added, %d removed, %d changed", msg.added, msg.removed, msg.changed)))
+
+	case configTickMsg:
+		cmds = append(cmds, m.checkConfigCmd(false), watchConfigCmd())
+
+	case configReloadedMsg:
+		m.configModTime = msg.modTime
+		if msg.err != nil {
+			cmds = append(cmds, m.showNotice(errorStyle.Render(msg.err.Error())))
+			break
+		}
+		previousDirs := strings.Join(m.cfg.FontDirs, "\x00")
+		updated, err := m.withConfig(msg.cfg)
+		if err != nil { // Keep running with the previous config
+			cmds = append(cmds, m.showNotice(errorStyle.Render(err.Error())))
+			break
+		}
+		m = updated
+		cmds = append(cmds, m.showNotice("Config reloaded"))
+		if strings.Join(m.cfg.FontDirs, "\x00") != previousDirs {
+			if m.fontList.Items() != nil {
+				previous := append([]fontMetadata(nil), m.fonts...)
+				cmds = append(cmds, m.rescanFontsCmd(previous))
+			} else if m.state == stateInputText || m.state == stateInitialLoading {
+				cmds = append(cmds, m.loadInitialFontsCmd())
+			}
+		}
+
+	case noticeTimeoutMsg:
+		if msg.id == m.noticeID {
+			m.notice = ""
+		}
+
+	case sudoWriteNeededMsg:
+		cmds = append(cmds, m.sudoWriteCmd(msg.pendingSave))
+
 	case statusTimeoutMsg:
 		m.statusMessage = ""
 		m.state = stateSelectFontWithPreview // Or stateInputText if preferred
 
 	case errorMsg:
-		m.errorMessage = msg.err.Error()
-		m.state = stateError
-		return m, nil // Stop further processing on error
+		return m.showError(msg), nil // Stop further processing on error
+
+	case diagnosticsMsg:
+		if m.state == stateError {
+			m.diagnostics = msg.checks
+		}
 
 	case tea.KeyMsg:
+		if msg.Paste { // Bracketed paste: text only, never keybindings (its String() is "[...]")
+			msg.Runes = []rune(joinPastedLines(string(msg.Runes), m.cfg.PasteJoin))
+		}
 		// Global quit
-		if key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit"))) {
-			return m, tea.Quit
+		if key.Matches(msg, m.keys.Quit) {
+			return m.requestQuit()
+		}
+		if m.editingText {
+			return m.updateEditText(msg)
+		}
+		if key.Matches(msg, m.keys.Suspend) {
+			return m, tea.Suspend
+		}
+		if key.Matches(msg, m.keys.ReloadConfig) {
+			return m, m.checkConfigCmd(true)
+		}
+		if key.Matches(msg, m.keys.ToggleLog) {
+			m.showLog = !m.showLog
+			return m, tea.WindowSize() // Re-layout for the changed footer height
+		}
+		if key.Matches(msg, m.keys.EditText) && m.canEditText() {
+			return m.openEditText()
 		}
 
 		switch m.state {
 		case stateInputText:
-			if msg.Type == tea.KeyEnter {
+			if key.Matches(msg, m.keys.Confirm) {
 				m.inputText = strings.TrimSpace(m.textInput.Value())
 				if m.inputText != "" {
-					m.state = stateLoadingPreviews
 					m.textInput.Blur()
-					cmds = append(cmds, m.spinner.Tick, m.generatePreviewsCmd())
+					if !m.fontsLoaded {
+						m.state = stateInitialLoading // Previews start once the scan finishes
+						return m, m.spinner.Tick
+					}
+					var cmd tea.Cmd
+					m, cmd = m.startPreviews()
+					cmds = append(cmds, cmd)
 				}
+			} else if key.Matches(msg, m.keys.Snippets) {
+				m.inputDraft = m.textInput.Value()
+				m.snippetList = m.newSnippetList()
+				m.textInput.Blur()
+				m.state = stateSnippetPicker
+			} else if key.Matches(msg, m.keys.History) {
+				m.inputDraft = m.textInput.Value()
+				m.historyList = m.newHistoryList()
+				m.textInput.Blur()
+				m.state = stateHistoryPicker
+			} else if key.Matches(msg, m.keys.Transforms) {
+				m.inputDraft = m.textInput.Value()
+				m.transformMenu = m.newTransformMenu(strings.TrimSpace(m.inputDraft))
+				m.textInput.Blur()
+				m.state = stateTransformPicker
 			} else {
 				var cmd tea.Cmd
 				m.textInput, cmd = m.textInput.Update(msg)
@@ -444,54 +1357,155 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case stateSelectFontWithPreview:
-			if key.Matches(msg, key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back"))) {
+			if key.Matches(msg, m.keys.Back) {
 				m.state = stateInputText
+				m.textInput.Placeholder = textPlaceholder
 				m.textInput.SetValue(m.inputText) // Keep previous text
 				m.textInput.Focus()
 				return m, nil
 			}
-			if msg.Type == tea.KeyEnter {
-				selected, ok := m.fontList.SelectedItem().(fontMetadata)
-				if ok {
-					m.selectedFontMeta = selected
-					m.state = stateGeneratingFullOutput
-					cmds = append(cmds, m.spinner.Tick, m.renderFullFigletCmd(m.selectedFontMeta.Path, m.inputText))
+			if key.Matches(msg, m.keys.Rescan) {
+				previous := append([]fontMetadata(nil), m.fonts...) // Snapshot, the command runs concurrently
+				return m, tea.Batch(m.fontList.NewStatusMessage("Rescanning fonts..."), m.rescanFontsCmd(previous))
+			}
+			if key.Matches(msg, m.keys.PurgeCache) {
+				return m, purgePreviewCacheCmd(m.cfg)
+			}
+			if key.Matches(msg, m.keys.RandomFont) && m.fontList.FilterState() != list.Filtering {
+				if n := len(m.fontList.VisibleItems()); n > 0 {
+					m.fontList.Select(m.rng.IntN(n))
+					if m.highlightMode() {
+						return m, m.scheduleHighlightRender()
+					}
+				}
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.AutoFit) && m.fontList.FilterState() != list.Filtering {
+				return m, tea.Batch(m.fontList.NewStatusMessage("Finding the biggest font that fits..."), m.autoFitCmd())
+			}
+			if key.Matches(msg, m.keys.EditFont) && m.fontList.FilterState() != list.Filtering {
+				if selected, ok := m.fontList.SelectedItem().(fontMetadata); ok {
+					return m, m.editFontCmd(selected)
+				}
+			}
+			if key.Matches(msg, m.keys.Showcase) && m.fontList.FilterState() != list.Filtering {
+				m.state = stateShowcase
+				return m, m.ensureShowcaseRender()
+			}
+			if key.Matches(msg, m.keys.SortUsage) && m.fontList.FilterState() != list.Filtering {
+				m.sortByUsage = !m.sortByUsage
+				order := "name"
+				if m.sortByUsage {
+					order = "most used"
+				}
+				return m, tea.Batch(m.fontList.SetItems(m.fontListItems()), m.fontList.NewStatusMessage("Sorted by "+order))
+			}
+			if key.Matches(msg, m.keys.Confirm) {
+				if selected, ok := m.fontList.SelectedItem().(fontMetadata); ok {
+					return m.chooseFont(selected)
 				}
 			}
+			if key.Matches(msg, m.fontList.KeyMap.Quit) && m.fontList.FilterState() != list.Filtering {
+				return m.requestQuit()
+			}
+			before, _ := m.highlightedFont()
 			var cmd tea.Cmd
 			m.fontList, cmd = m.fontList.Update(msg)
 			cmds = append(cmds, cmd)
+			if after, ok := m.highlightedFont(); m.highlightMode() && ok && after.Path != before.Path {
+				cmds = append(cmds, m.scheduleHighlightRender())
+			}
 		
+		case stateShowcase:
+			switch {
+			case key.Matches(msg, m.keys.ShowcasePrev):
+				return m.showcaseStep(-1)
+			case key.Matches(msg, m.keys.ShowcaseNext):
+				return m.showcaseStep(1)
+			case key.Matches(msg, m.keys.Confirm):
+				if selected, ok := m.highlightedFont(); ok {
+					return m.chooseFont(selected)
+				}
+			case key.Matches(msg, m.keys.Back):
+				m.state = stateSelectFontWithPreview
+				if m.highlightMode() {
+					return m, m.scheduleHighlightRender()
+				}
+			}
+
 		case stateOutputChoice:
-			switch strings.ToLower(msg.String()) {
-			case "t":
-				m.figletViewport = viewport.New(m.termWidth-docStyle.GetHorizontalFrameSize(), m.termHeight - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView()) -2)
-				m.figletViewport.Style = figletOutputStyle
-				m.figletViewport.SetContent(m.fullFigletOutput)
-				m.figletViewport.GotoTop()
-				m.state = stateDisplayFiglet
-				m.statusMessage = ""
-			case "f":
-				m.textInput.Placeholder = "Enter filename (e.g., output.txt)"
-				m.textInput.SetValue("") // Clear for filename
-				m.textInput.Focus()
-				m.state = stateSaveFileNameInput
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				if t, ok := m.outputMenu.SelectedItem().(outputTarget); ok {
+					var cmd tea.Cmd
+					m, cmd = t.Choose(m)
+					cmds = append(cmds, cmd)
+				}
+			case key.Matches(msg, m.keys.CycleFilter): // Cycle post-processing filters
+				m.filterIndex = (m.filterIndex + 1) % len(outputFilters)
+				m.statusMessage = m.outputChoicePrompt()
+			case key.Matches(msg, m.keys.Back): // Allow escape from this choice
 				m.statusMessage = ""
-			case "esc": // Allow escape from this choice
+				if m.fontList.Items() == nil { // Recalled from history before the font scan finished
+					m.recallPending = false
+					m = m.backToTextInput(m.inputText)
+					break
+				}
 				m.state = stateSelectFontWithPreview
-				m.statusMessage = ""
+			case key.Matches(msg, m.outputMenu.KeyMap.Quit): // "q", after Back since the list also binds esc
+				return m.requestQuit()
+			default:
+				if t, ok := m.outputShortcut(msg); ok {
+					var cmd tea.Cmd
+					m, cmd = t.Choose(m)
+					cmds = append(cmds, cmd)
+					break
+				}
+				var cmd tea.Cmd
+				m.outputMenu, cmd = m.outputMenu.Update(msg) // Navigation
+				cmds = append(cmds, cmd)
 			}
 
 		case stateSaveFileNameInput:
-			if msg.Type == tea.KeyEnter {
+			if key.Matches(msg, m.keys.Confirm) {
 				filename := strings.TrimSpace(m.textInput.Value())
 				if filename != "" {
-					m.textInput.Blur()
-					cmds = append(cmds, m.saveToFileCmd(filename, m.fullFigletOutput))
+					if limit := m.widthLimit(); limit > 0 {
+						if w := measureBanner(m.outputText()).Width; w > limit {
+							m.textInput.Blur()
+							m.state = stateWidthWarning
+							m.statusMessage = fmt.Sprintf("Output is %d columns wide, over the %d column limit. (r)e-render at %d columns or (s)ave anyway?", w, limit, limit)
+							return m, nil
+						}
+					}
+					return m.beginSave(filename)
 				}
-			} else if msg.Type == tea.KeyEsc {
-				m.state = stateOutputChoice // Go back to T/F choice
-				m.statusMessage = "Output to (t)erminal or save to (f)ile?"
+			} else if key.Matches(msg, m.keys.CycleFormat) { // Cycle export formats
+				previousDefault := m.defaultSavePath()
+				m.exportIndex = (m.exportIndex + 1) % len(exportFormats)
+				m.textInput.Placeholder = m.filenamePlaceholder()
+				if m.textInput.Value() == previousDefault { // Don't clobber a filename the user typed
+					m.textInput.SetValue(m.defaultSavePath())
+					m.textInput.CursorEnd()
+				}
+				m.statusMessage = ""
+			} else if key.Matches(msg, m.keys.ToggleColors) { // Toggle keeping colors in the saved file
+				m.saveOpts.Colors = !m.saveOpts.Colors
+				m.statusMessage = ""
+			} else if key.Matches(msg, m.keys.ToggleCRLF) { // Toggle CRLF line endings
+				m.saveOpts.CRLF = !m.saveOpts.CRLF
+				m.statusMessage = ""
+			} else if key.Matches(msg, m.keys.ToggleBOM) { // Toggle UTF-8 byte order mark
+				m.saveOpts.BOM = !m.saveOpts.BOM
+				m.statusMessage = ""
+			} else if key.Matches(msg, m.keys.ToggleFooter) && m.cfg.Footer.Text != "" { // Toggle the configured footer
+				m.saveOpts.Footer = !m.saveOpts.Footer
+				m.statusMessage = ""
+			} else if key.Matches(msg, m.keys.ToggleCenter) && m.cfg.CenterWidth > 0 { // Toggle centering in center_width
+				m.saveOpts.Center = !m.saveOpts.Center
+				m.statusMessage = ""
+			} else if key.Matches(msg, m.keys.Back) {
+				m = m.toOutputChoice() // Go back to the output menu
 				m.textInput.Blur()
 			} else {
 				var cmd tea.Cmd
@@ -499,56 +1513,455 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmds = append(cmds, cmd)
 			}
 
+		case stateSnippetPicker:
+			if m.snippetList.FilterState() == list.Filtering { // Keys go to the filter input
+				var cmd tea.Cmd
+				m.snippetList, cmd = m.snippetList.Update(msg)
+				cmds = append(cmds, cmd)
+				break
+			}
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m = m.backToTextInput(m.inputDraft)
+			case key.Matches(msg, m.snippetList.KeyMap.Quit): // "q", after Back since the list also binds esc
+				return m.requestQuit()
+			case key.Matches(msg, m.keys.Confirm):
+				if sn, ok := m.snippetList.SelectedItem().(snippet); ok {
+					m = m.backToTextInput(sn.Text)
+				}
+			case key.Matches(msg, m.keys.SnippetAdd):
+				if strings.TrimSpace(m.inputDraft) == "" {
+					cmds = append(cmds, m.snippetList.NewStatusMessage("Type the text first, then add it as a snippet"))
+					break
+				}
+				m.textInput.Placeholder = "Snippet name"
+				m.textInput.SetValue("")
+				m.textInput.Focus()
+				m.state = stateSnippetName
+			case key.Matches(msg, m.keys.SnippetDelete):
+				if sn, ok := m.snippetList.SelectedItem().(snippet); ok {
+					var kept []snippet
+					for _, other := range m.cfg.Snippets {
+						if other != sn {
+							kept = append(kept, other)
+						}
+					}
+					updated, err := m.withSnippets(kept)
+					if err != nil {
+						return m, func() tea.Msg { return errorMsg{err: err} }
+					}
+					m = updated
+					m.snippetList = m.newSnippetList()
+				}
+			default:
+				var cmd tea.Cmd
+				m.snippetList, cmd = m.snippetList.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+
+		case stateHistoryPicker:
+			if m.historyList.FilterState() == list.Filtering { // Keys go to the filter input
+				var cmd tea.Cmd
+				m.historyList, cmd = m.historyList.Update(msg)
+				cmds = append(cmds, cmd)
+				break
+			}
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m = m.backToTextInput(m.inputDraft)
+			case key.Matches(msg, m.historyList.KeyMap.Quit): // "q", after Back since the list also binds esc
+				return m.requestQuit()
+			case key.Matches(msg, m.keys.Confirm):
+				if e, ok := m.historyList.SelectedItem().(historyEntry); ok {
+					var cmd tea.Cmd
+					m, cmd = m.recallHistory(e)
+					cmds = append(cmds, cmd)
+				}
+			default:
+				var cmd tea.Cmd
+				m.historyList, cmd = m.historyList.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+
+		case stateTransformPicker:
+			switch {
+			case key.Matches(msg, m.keys.Back):
+				m = m.backToTextInput(m.inputDraft)
+			case key.Matches(msg, m.transformMenu.KeyMap.Quit): // "q", after Back since the list also binds esc
+				return m.requestQuit()
+			case key.Matches(msg, m.keys.Confirm):
+				m.transformIndex = m.transformMenu.Index()
+				m = m.backToTextInput(m.inputDraft)
+			default:
+				var cmd tea.Cmd
+				m.transformMenu, cmd = m.transformMenu.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+
+		case stateSnippetName:
+			switch {
+			case key.Matches(msg, m.keys.Confirm):
+				name := strings.TrimSpace(m.textInput.Value())
+				if name == "" {
+					break
+				}
+				snippets := append(append([]snippet(nil), m.cfg.Snippets...), snippet{Name: name, Text: strings.TrimSpace(m.inputDraft)})
+				updated, err := m.withSnippets(snippets)
+				if err != nil {
+					return m, func() tea.Msg { return errorMsg{err: err} }
+				}
+				m = updated
+				m.textInput.Blur()
+				m.snippetList = m.newSnippetList()
+				m.state = stateSnippetPicker
+			case key.Matches(msg, m.keys.Back):
+				m.textInput.Blur()
+				m.state = stateSnippetPicker
+			default:
+				var cmd tea.Cmd
+				m.textInput, cmd = m.textInput.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+
+		case stateWidthWarning:
+			switch strings.ToLower(msg.String()) {
+			case "r":
+				m.renderWidth = m.widthLimit()
+				m.resumeSave = true
+				m.state = stateGeneratingFullOutput
+				cmds = append(cmds, m.spinner.Tick, m.renderFullFigletCmd(m.selectedFontMeta.Path, m.renderText()))
+			case "s":
+				return m.beginSave(strings.TrimSpace(m.textInput.Value()))
+			case "esc":
+				m.state = stateSaveFileNameInput
+				m.statusMessage = ""
+				m.textInput.Focus()
+			}
+
+		case stateConfirmSystemWrite:
+			switch strings.ToLower(msg.String()) {
+			case "y":
+				m.statusMessage = ""
+				cmds = append(cmds, m.writeSystemFileCmd(m.pendingSave))
+			case "n", "esc":
+				m.state = stateSaveFileNameInput
+				m.statusMessage = ""
+				m.textInput.Focus()
+			}
+
+		case stateConfirmMkdir:
+			switch strings.ToLower(msg.String()) {
+			case "y":
+				dir := filepath.Dir(m.pendingSave.path)
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					m.state = stateSaveFileNameInput
+					m.statusMessage = errorStyle.Render(fmt.Sprintf("Couldn't create %s: %v", dir, err))
+					cmds = append(cmds, m.textInput.Focus())
+					break
+				}
+				m.logAction("Created directory %s", dir)
+				var cmd tea.Cmd
+				m, cmd = m.startSave(m.pendingSave.path, m.pendingSave.content)
+				cmds = append(cmds, cmd)
+			case "n", "esc":
+				m.state = stateSaveFileNameInput
+				m.statusMessage = ""
+				m.textInput.Focus()
+			}
+
+		case stateConfirmOverwrite:
+			switch strings.ToLower(msg.String()) {
+			case "o":
+				var cmd tea.Cmd
+				m, cmd = m.startSave(m.pendingSave.path, m.pendingSave.content)
+				cmds = append(cmds, cmd)
+			case "r":
+				var cmd tea.Cmd
+				m, cmd = m.startSave(nextFreeName(m.pendingSave.path), m.pendingSave.content)
+				cmds = append(cmds, cmd)
+			case "d":
+				vp, err := m.newDiffViewport()
+				if err != nil {
+					m.statusMessage = errorStyle.Render(fmt.Sprintf("Can't diff: %v", err))
+					break
+				}
+				m.diffViewport = vp
+				m.state = stateOverwriteDiff
+			case "n", "esc":
+				m.state = stateSaveFileNameInput
+				m.statusMessage = ""
+				m.textInput.Focus()
+			}
+
+		case stateSaving:
+			if key.Matches(msg, m.keys.Back) {
+				m.saving.canceled.Store(true)
+			}
+
+		case stateOverwriteDiff:
+			if key.Matches(msg, m.keys.CloseView) {
+				m.state = stateConfirmOverwrite
+				m.statusMessage = m.overwritePrompt()
+				break
+			}
+			var cmd tea.Cmd
+			m.diffViewport, cmd = m.diffViewport.Update(msg)
+			cmds = append(cmds, cmd)
+
+		case stateCompose:
+			return m.updateCompose(msg)
+
 		case stateDisplayFiglet:
-			if key.Matches(msg, key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc/q", "back"))) {
+			if m, cmd, ok := m.updateWidthKeys(msg); ok {
+				return m, cmd
+			}
+			if key.Matches(msg, m.keys.CloseView) {
 				m.state = stateSelectFontWithPreview
 			}
+			if key.Matches(msg, m.keys.CycleFilter) {
+				m.filterIndex = (m.filterIndex + 1) % len(outputFilters)
+				m.figletViewport.SetContent(m.outputText())
+			}
 			var cmd tea.Cmd
 			m.figletViewport, cmd = m.figletViewport.Update(msg)
 			cmds = append(cmds, cmd)
 		
+		case stateConfirmQuit:
+			switch msg.String() {
+			case "y", "Y":
+				return m, tea.Quit
+			case "n", "N", "esc":
+				m.state = m.quitReturnState
+			}
+
 		case stateShowStatusMessage: // Usually waiting for timeout or a key press
 		    if key.Matches(msg, key.NewBinding(key.WithKeys("enter", "esc"), key.WithHelp("any key", "continue"))) {
 				m.statusMessage = ""
 				m.state = stateSelectFontWithPreview
 			}
 
-		case stateError: // Allow quitting from error state
-			if key.Matches(msg, key.NewBinding(key.WithKeys("q", "esc", "enter"), key.WithHelp("any key", "quit"))) {
-				return m, tea.Quit
-			}
+		case stateError:
+			return m.updateError(msg)
 		}
 	}
+	cmds = append(cmds, m.reloadEvictedPreviews())
 	return m, tea.Batch(cmds...)
 }
 
+// requestQuit quits, unless there's an unsaved render to ask about first.
+// Pressing quit again at the question quits.
+func (m model) requestQuit() (tea.Model, tea.Cmd) {
+	if !m.unsaved || m.printOnExit || !m.cfg.ConfirmQuit || m.state == stateConfirmQuit { // A printed render isn't lost
+		return m, tea.Quit
+	}
+	m.quitReturnState = m.state
+	m.state = stateConfirmQuit
+	return m, nil
+}
+
+// quitPrompt asks whether to throw away the unsaved render.
+func (m model) quitPrompt() string {
+	where := ""
+	if m.historySize() > 0 {
+		where = " (it stays in the recent outputs)"
+	}
+	return fmt.Sprintf("The render in %s hasn't been saved%s. Quit anyway? (y/n)", m.selectedFontMeta.Name, where)
+}
+
+// chooseFont renders the chosen font in full and moves on to the output choice.
+func (m model) chooseFont(font fontMetadata) (model, tea.Cmd) {
+	if m.pickOnly {
+		m.pickedFont = font
+		return m, tea.Quit
+	}
+	m.selectedFontMeta = font
+	m.renderWidth = 0
+	m.state = stateGeneratingFullOutput
+	return m, tea.Batch(m.spinner.Tick, m.renderFullFigletCmd(font.Path, m.renderText()))
+}
+
+// backToTextInput returns to text entry with the given text.
+func (m model) backToTextInput(text string) model {
+	m.textInput.Placeholder = textPlaceholder
+	m.textInput.SetValue(text)
+	m.textInput.CursorEnd()
+	m.textInput.Focus()
+	m.state = stateInputText
+	return m
+}
+
+// startPreviews moves on from text entry: bulk mode renders every preview
+// first, highlight mode shows the list right away.
+func (m model) startPreviews() (model, tea.Cmd) {
+	if m.highlightMode() {
+		if m.previewed.text != m.renderText() { // Text changed, drop renders of the old one
+			m.highlightRenders = make(map[string]string)
+			m.showcaseRenders = nil
+			m.previewed = previewTarget{text: m.renderText()} // Renders are at the pane's width
+		}
+		m = m.enterFontList()
+		if font, ok := m.highlightedFont(); ok {
+			return m, m.renderHighlightCmd(font)
+		}
+		return m, nil
+	}
+	if m.previewed == (previewTarget{m.renderText(), previewWidth(m.termWidth)}) { // Confirmed the same text again, the previews are still good
+		return m.enterFontList(), nil
+	}
+	m.showcaseRenders = nil
+	m, cmd := m.streamPreviews()
+	return m.enterFontList(), cmd
+}
+
+// enterFontList builds the font list from m.fonts and shows it.
+func (m model) enterFontList() model {
+	items := m.fontListItems()
+
+	delegate := newItemDelegate(m.delegatePreviewLines(), m.previews)
+	listHeight := m.termHeight - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView()) -2
+	newList := list.New(items, delegate, m.fontListWidth(), listHeight)
+	newList.Title = m.fontListTitle()
+	newList.Styles.Title = listTitleStyle
+	newList.Styles.HelpStyle = helpStyle.MarginTop(0) // Adjust help style margin for list
+	newList.SetShowStatusBar(true) // Show item count, etc.
+	newList.SetFilteringEnabled(true)
+	newList.Styles.StatusBar = statusMessageStyle.Padding(0,1)
+
+	m.fontList = newList
+	m.state = stateSelectFontWithPreview
+	return m
+}
+
+// delegatePreviewLines is the in-list preview height; highlight mode lists names only.
+func (m model) delegatePreviewLines() int {
+	if m.highlightMode() {
+		return 0
+	}
+	return previewLines
+}
+
+// widthLimit is the narrowest of the configured and export preset maximum
+// widths, or 0 if neither applies.
+func (m model) widthLimit() int {
+	limit := m.cfg.MaxWidth
+	if f := exportFormats[m.exportIndex]; f.MaxWidth > 0 && (limit == 0 || f.MaxWidth < limit) {
+		limit = f.MaxWidth
+	}
+	return limit
+}
+
+// beginSave exports the banner and writes it, asking for confirmation first
+// when the target is a system file. Export errors stay on the filename screen
+// so another format can be picked.
+func (m model) beginSave(filename string) (tea.Model, tea.Cmd) {
+	m.state = stateSaveFileNameInput
+	content, err := m.exportedOutput()
+	if err != nil {
+		m.statusMessage = errorStyle.Render(err.Error())
+		m.textInput.Focus()
+		return m, nil
+	}
+	m.textInput.Blur()
+	m.statusMessage = ""
+	if m.isPresetTarget(filename) {
+		m.pendingSave = pendingSave{path: filename, content: content}
+		m.state = stateConfirmSystemWrite
+		m.statusMessage = fmt.Sprintf("Write banner to %s (using sudo if needed)? (y/n)", filename)
+		return m, nil
+	}
+	if dir := filepath.Dir(filename); !dirExists(dir) {
+		m.pendingSave = pendingSave{path: filename, content: content}
+		m.state = stateConfirmMkdir
+		m.statusMessage = fmt.Sprintf("Directory %s doesn't exist. Create it? (y/n)", dir)
+		return m, nil
+	}
+	if differs, _ := existingDiffers(filename, content); differs { // Read errors surface when writing
+		m.pendingSave = pendingSave{path: filename, content: content}
+		m.state = stateConfirmOverwrite
+		m.statusMessage = m.overwritePrompt()
+		return m, nil
+	}
+	return m.startSave(filename, content)
+}
+
+func (m model) overwritePrompt() string {
+	return fmt.Sprintf("%s already exists with different content. (o)verwrite, (r)ename to %s, or (d)iff?",
+		m.pendingSave.path, filepath.Base(nextFreeName(m.pendingSave.path)))
+}
+
 // --- View ---
 func (m model) headerView() string {
 	title := titleStyle.Render("FontLet GO v2 🎨")
-	var subtitle string
-	// (Subtitles can be added based on state if desired)
+	subtitle := m.breadcrumbView()
+	if m.notice != "" {
+		subtitle += "  " + statusMessageStyle.Padding(0).Render(m.notice)
+	}
 	return fmt.Sprintf("%s\n%s", title, subtitle)
 }
 
 func (m model) footerView() string {
+	if m.showLog {
+		return m.actionLogView() + "\n" + m.helpLine()
+	}
+	return m.helpLine()
+}
+
+// helpLine shows the keys of the current state.
+func (m model) helpLine() string {
 	var help string
 	switch m.state {
 	case stateInputText:
-		help = helpStyle.Render("enter: confirm text • ctrl+c: quit")
+		bindings := []key.Binding{describe(m.keys.Confirm, "confirm text"), m.keys.Snippets, m.keys.History, m.keys.Transforms, m.keys.Quit}
+		if !m.fontsLoaded {
+			bindings = append(bindings, infoBinding("", "scanning fonts..."))
+		}
+		if m.cfg.CharLimit > 0 {
+			bindings = append(bindings, infoBinding("", fmt.Sprintf("%d/%d characters", len([]rune(m.textInput.Value())), m.cfg.CharLimit)))
+		}
+		help = helpView(bindings...)
 	case stateSelectFontWithPreview:
 		// List provides its own help usually, or we can add more context.
 		// help = m.fontList.View() // This would render the list itself. We want just help.
-		help = helpStyle.Render("↑/↓: navigate • enter: select font • esc: change text • ctrl+c: quit")
+		help = helpView(infoBinding("↑/↓", "navigate"), describe(m.keys.Confirm, "select font"), m.keys.RandomFont, m.keys.AutoFit, m.keys.Showcase, m.keys.SortUsage, m.keys.EditFont, m.keys.Rescan, m.keys.PurgeCache, m.keys.EditText, describe(m.keys.Back, "change text"), m.keys.Quit)
+	case stateShowcase:
+		help = helpView(m.keys.ShowcasePrev, m.keys.ShowcaseNext, describe(m.keys.Confirm, "choose font"), m.keys.EditText, describe(m.keys.Back, "back to font list"), m.keys.Quit)
+	case stateCompose:
+		help = m.composeHelp()
 	case stateDisplayFiglet:
-		help = helpStyle.Render("↑/↓/pgup/pgdn: scroll • esc/q: back to font list • ctrl+c: quit")
+		help = helpView(append(append([]key.Binding{infoBinding("↑/↓/pgup/pgdn", "scroll"), m.keys.CycleFilter}, m.widthHelp()...), m.editTextHelp(), m.keys.CloseView, m.keys.Quit)...)
 	case stateOutputChoice:
-		help = helpStyle.Render("t: terminal • f: file • esc: back to font list • ctrl+c: quit")
+		help = helpView(infoBinding("↑/↓", "navigate"), describe(m.keys.Confirm, "choose"), m.keys.CycleFilter, m.editTextHelp(), describe(m.keys.Back, "back to font list"), m.keys.Quit)
 	case stateSaveFileNameInput:
-		help = helpStyle.Render("enter: save file • esc: cancel save • ctrl+c: quit")
-	case stateInitialLoading, stateLoadingPreviews, stateGeneratingFullOutput:
-		return fmt.Sprintf("%s %s", m.spinner.View(), "Processing...")
+		help = helpView(describe(m.keys.Confirm, "save file"), m.keys.CycleFormat, m.keys.ToggleColors, m.keys.ToggleCRLF, m.keys.ToggleBOM, m.footerHelp(), m.centerHelp(), describe(m.keys.Back, "cancel save"), m.keys.Quit)
+	case stateConfirmSystemWrite:
+		help = helpView(infoBinding("y", "write"), infoBinding("n/esc", "back to filename"), m.keys.Quit)
+	case stateConfirmMkdir:
+		help = helpView(infoBinding("y", "create and save"), infoBinding("n/esc", "back to filename"), m.keys.Quit)
+	case stateConfirmOverwrite:
+		help = helpView(infoBinding("o", "overwrite"), infoBinding("r", "save as "+filepath.Base(nextFreeName(m.pendingSave.path))), infoBinding("d", "diff"), infoBinding("n/esc", "back to filename"), m.keys.Quit)
+	case stateSaving:
+		help = helpView(describe(m.keys.Back, "cancel"), m.keys.Quit)
+		if m.saving.canceled.Load() {
+			help = helpStyle.Render("Canceling...")
+		}
+	case stateOverwriteDiff:
+		help = helpView(infoBinding("↑/↓/pgup/pgdn", "scroll"), describe(m.keys.CloseView, "back"), m.keys.Quit)
+	case stateSnippetPicker:
+		help = helpView(infoBinding("↑/↓", "navigate"), describe(m.keys.Confirm, "use snippet"), m.keys.SnippetAdd, m.keys.SnippetDelete, infoBinding("/", "filter"), describe(m.keys.Back, "back to text"), m.keys.Quit)
+	case stateHistoryPicker:
+		help = helpView(infoBinding("↑/↓", "navigate"), describe(m.keys.Confirm, "recall output"), infoBinding("/", "filter"), describe(m.keys.Back, "back to text"), m.keys.Quit)
+	case stateTransformPicker:
+		help = helpView(infoBinding("↑/↓", "navigate"), describe(m.keys.Confirm, "use transform"), describe(m.keys.Back, "back to text"), m.keys.Quit)
+	case stateSnippetName:
+		help = helpView(describe(m.keys.Confirm, "save snippet"), describe(m.keys.Back, "cancel"), m.keys.Quit)
+	case stateWidthWarning:
+		help = helpView(infoBinding("r", "re-render narrower"), infoBinding("s", "save anyway"), infoBinding("esc", "back to filename"), m.keys.Quit)
+	case stateInitialLoading, stateGeneratingFullOutput:
+		return fmt.Sprintf("%s %s", m.spinner.View(), m.progressView())
 	case stateError:
-		help = helpStyle.Render("Press any key to quit.")
+		help = m.errorHelp()
+	case stateConfirmQuit:
+		help = helpView(infoBinding("y", "quit"), infoBinding("n/esc", "keep working"), describe(m.keys.Quit, "quit"))
 	case stateShowStatusMessage:
 		help = helpStyle.Render("Press Enter or Esc to continue...")
 	}
@@ -563,23 +1976,68 @@ func (m model) View() string {
 	s.WriteString(m.headerView())
 	s.WriteString("\n") // Some space after header
 
-	mainContentStyle := lipgloss.NewStyle().Width(m.termWidth - docStyle.GetHorizontalFrameSize())
+	mainContentStyle := renderer.NewStyle().Width(m.termWidth - docStyle.GetHorizontalFrameSize())
 
 	switch m.state {
 	case stateError:
-		s.WriteString(mainContentStyle.Render(errorStyle.Render(m.errorMessage)))
-	case stateInitialLoading, stateLoadingPreviews, stateGeneratingFullOutput:
+		s.WriteString(mainContentStyle.Render(m.errorView()))
+	case stateInitialLoading, stateGeneratingFullOutput:
 		s.WriteString(mainContentStyle.Render(fmt.Sprintf("\n%s Please wait...\n", m.spinner.View())))
 	case stateInputText:
 		s.WriteString(m.textInput.View())
+		if v := m.textInput.Value(); lipgloss.Width(v) > m.textInput.Width { // Long text scrolls in the input, show all of it wrapped
+			s.WriteString("\n\n")
+			s.WriteString(mainContentStyle.Render(sourceStyle.Render(v)))
+		}
+		if t := textTransforms[m.transformIndex]; m.transformIndex != 0 {
+			s.WriteString("\n\n")
+			s.WriteString(mainContentStyle.Render(sourceStyle.Render(fmt.Sprintf("Rendered as %s: %s", t.Name, t.Apply(strings.TrimSpace(m.textInput.Value()))))))
+		}
 	case stateSelectFontWithPreview:
-		s.WriteString(m.fontList.View()) // List handles its own height/width
+		if m.highlightMode() {
+			listView := m.fontList.View() // List handles its own height/width
+			s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, listView, "  ", m.highlightPaneView(lipgloss.Height(listView))))
+		} else {
+			s.WriteString(m.fontList.View()) // List handles its own height/width
+		}
+	case stateShowcase:
+		s.WriteString(m.showcaseView(m.termHeight - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView()) - 2))
 	case stateDisplayFiglet:
 		s.WriteString(m.figletViewport.View())
+	case stateCompose:
+		s.WriteString(m.composeView())
 	case stateOutputChoice:
-		s.WriteString(mainContentStyle.Render(statusMessageStyle.Render(m.statusMessage)))
+		s.WriteString(statusMessageStyle.Render(m.statusMessage))
+		s.WriteString("\n")
+		s.WriteString(m.outputMenu.View())
 	case stateSaveFileNameInput:
 		s.WriteString(m.textInput.View()) // Re-using textInput for filename
+		s.WriteString("\n")
+		s.WriteString(statusMessageStyle.Render(m.saveOptionsView()))
+		if m.statusMessage != "" {
+			s.WriteString("\n")
+			s.WriteString(mainContentStyle.Render(m.statusMessage)) // Already styled error
+		}
+	case stateSnippetPicker:
+		s.WriteString(m.snippetList.View())
+	case stateHistoryPicker:
+		s.WriteString(m.historyList.View())
+	case stateTransformPicker:
+		s.WriteString(m.transformMenu.View())
+	case stateSnippetName:
+		s.WriteString(statusMessageStyle.Render(fmt.Sprintf("Save %q as a snippet named:", strings.TrimSpace(m.inputDraft))))
+		s.WriteString("\n")
+		s.WriteString(m.textInput.View())
+	case stateSaving:
+		s.WriteString(mainContentStyle.Render(fmt.Sprintf("\n%s Saving to %s...\n\n%s\n", m.spinner.View(), m.saving.path, m.saving.progressView(m.termWidth))))
+	case stateOverwriteDiff:
+		s.WriteString(statusMessageStyle.Render(fmt.Sprintf("Changes to %s (- on disk, + to save):", m.pendingSave.path)))
+		s.WriteString("\n")
+		s.WriteString(m.diffViewport.View())
+	case stateConfirmSystemWrite, stateConfirmMkdir, stateConfirmOverwrite, stateWidthWarning:
+		s.WriteString(mainContentStyle.Render(statusMessageStyle.Render(m.statusMessage)))
+	case stateConfirmQuit:
+		s.WriteString(mainContentStyle.Render(statusMessageStyle.Render(m.quitPrompt())))
 	case stateShowStatusMessage:
 	    s.WriteString(mainContentStyle.Render(m.statusMessage)) // Already styled success/error
 	}
@@ -587,21 +2045,116 @@ func (m model) View() string {
 	s.WriteString("\n\n") // Space before footer
 	s.WriteString(m.footerView())
 
+	if m.editingText {
+		return m.editTextView(docStyle.Render(s.String()))
+	}
 	return docStyle.Render(s.String())
 }
 
 
 func main() {
-	m := initialModel()
-	if m.state == stateError && m.errorMessage != "" { // Check if error occurred in initialModel
-		fmt.Fprintln(os.Stderr, errorStyle.Render(m.errorMessage))
-		os.Exit(1)
+	// Global flags come before the subcommand, e.g. `fontlet --fake-figlet preview ...`
+	seed := flag.Uint64("seed", 0, "seed for random font picks (r in the font list), for reproducible sessions")
+	flag.BoolVar(&useFakeFiglet, "fake-figlet", false, "render with built-in stand-in fonts instead of figlet, for tests and development")
+	colorMode := flag.String("color", "auto", "when to use colors: auto (on terminals), always or never")
+	stdio := flag.Bool("stdio", false, "serve JSON-lines requests on stdin for editor integrations instead of starting the TUI")
+	insert := flag.Bool("insert", false, "exit as soon as a font is chosen and print the banner between marker lines, for editor plugins")
+	outFifo := flag.String("out-fifo", "", "with --insert, write the banner to this named pipe instead of stdout")
+	follow := flag.Bool("follow", false, "render each line of stdin as it arrives, e.g. tail -f build.log | fontlet --follow -f big")
+	followFont := flag.String("f", "standard", `font for --follow ("random" for a random one)`)
+	flag.BoolVar(&noState, "no-state", noStateFromEnv(), "keep nothing on disk (config from $FONTLET_CONFIG, no history or caches), for containers and CI; also set by $FONTLET_NO_STATE")
+	listFonts := flag.Bool("list-fonts", false, "list the fonts and exit, like `fontlet list`")
+	var scripted scriptedOptions
+	flag.StringVar(&scripted.font, "font", "", `render the text in this font without the TUI ("random" or "auto" work too)`)
+	flag.StringVar(&scripted.text, "text", "", "text to render; without --font or --output, the TUI starts with it")
+	flag.IntVar(&scripted.width, "width", 0, "with --font or --output, output width in columns (default: the terminal's, or 80)")
+	flag.StringVar(&scripted.output, "output", "", "with --font, write the banner to this file instead of stdout")
+	printOnExit := flag.Bool("print-on-exit", false, "print the last full render to stdout after the TUI exits, e.g. fontlet --print-on-exit | tee banner.txt")
+	inputFile := flag.String("i", "", "read the text from this file (- for stdin); piped text is read without it")
+	flag.Parse()
+	if err := setColorMode(*colorMode); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	globalOptions = tuiOptions{seed: *seed, seeded: flagSet(flag.CommandLine, "seed")}
+
+	if *stdio {
+		exitOnError(runStdio(os.Stdin, os.Stdout))
+		return
+	}
+
+	if *follow {
+		exitOnError(runFollow(os.Stdin, os.Stdout, *followFont))
+		return
+	}
+
+	if handled, err := runSubcommand(flag.Args()); handled {
+		exitOnError(err)
+		return
+	}
+
+	if *listFonts {
+		exitOnError(runList(nil, os.Stdout))
+		return
+	}
+
+	if scripted.text == "" {
+		text, err := readInputText(*inputFile, os.Stdin)
+		exitOnError(err)
+		scripted.text = text
+	} else if *inputFile != "" {
+		exitOnError(fmt.Errorf("--text and -i both give the text, use one"))
+	}
+
+	if scripted.scripted() {
+		exitOnError(runScripted(scripted, os.Stdout))
+		return
+	}
+
+	opts := globalOptions
+	opts.text = scripted.text
+	opts.insert = *insert || *outFifo != ""
+	opts.print = *printOnExit
+	final, err := runTUI(opts)
+	if err == nil && opts.insert && final.fullFigletOutput != "" {
+		err = writeInsertion(final, os.Stdout, *outFifo)
+	}
+	if err == nil {
+		err = writeOnExit(final, os.Stdout)
 	}
+	exitOnError(err)
+}
 
-	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
+// exitOnError reports err on stderr and exits with status 1, if there is one.
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, stderrError(err.Error()))
 		os.Exit(1)
 	}
 }
 
+// runTUI runs an interactive session and returns its final model.
+func runTUI(opts tuiOptions) (model, error) {
+	m := initialModel(opts)
+	if m.state == stateError && m.errorMessage != "" { // Check if error occurred in initialModel
+		return m, errors.New(m.errorMessage)
+	}
+
+	options := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if tty := ttyForTUI(); tty != nil { // Stdout is for the banner, see printexit.go
+		defer tty.Close()
+		options = append(options, tea.WithOutput(tty))
+	}
+	p := tea.NewProgram(m, options...)
+	forwardSuspendSignals(p)
+	stopWatching := killOnHangup(p)
+	final, err := p.Run()
+	stopWatching()
+	shutdown()
+	if err != nil {
+		return m, fmt.Errorf("error running program: %w", err)
+	}
+	return final.(model), nil
+}
+
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsPresetTarget(t *testing.T) {
+	formatIndex := func(name string) int {
+		for i, f := range exportFormats {
+			if f.Name == name {
+				return i
+			}
+		}
+		t.Fatalf("no export format %q", name)
+		return 0
+	}
+	cfg := config{SSHBannerPath: "/usr/local/etc/ssh/banner"}
+	tests := []struct {
+		format, path string
+		want         bool
+	}{
+		{"SSH banner", "/usr/local/etc/ssh/banner", true},
+		{"SSH banner", "/usr/local/etc/ssh/../ssh/banner", true},
+		{"SSH banner", "/etc/issue", false},
+		{"/etc/issue (getty)", "/etc/issue", true},
+		{"/etc/issue (getty)", "/etc/motd", false},
+		{"plain text", "/etc/issue", false},
+	}
+	for _, tt := range tests {
+		m := model{cfg: cfg, exportIndex: formatIndex(tt.format)}
+		if got := m.isPresetTarget(tt.path); got != tt.want {
+			t.Errorf("isPresetTarget(%q) with %s = %v, want %v", tt.path, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestSavedBannerColors(t *testing.T) {
+	filter := func(name string) int {
+		for i, f := range outputFilters {
+			if f.Name == name {
+				return i
+			}
+		}
+		t.Fatalf("no filter %q", name)
+		return 0
+	}
+	tests := []struct {
+		filter      string
+		colors      bool
+		wantEscapes bool
+	}{
+		{"none", false, false},
+		{"none", true, false}, // The theme color isn't saved
+		{"rainbow", false, false},
+		{"rainbow", true, true},
+		{"metal", true, true},
+	}
+	for _, tt := range tests {
+		m := model{fullFigletOutput: " _ \n|_|\n", filterIndex: filter(tt.filter), saveOpts: saveOptions{Colors: tt.colors}}
+		if got := m.savedBanner(); strings.Contains(got, "\x1b[") != tt.wantEscapes {
+			t.Errorf("savedBanner with %s, colors %v = %q, want escapes: %v", tt.filter, tt.colors, got, tt.wantEscapes)
+		}
+	}
+}
+
+// TestRecallBeforeScan recalls a history entry while fonts are still being
+// scanned: the font is selected once the scan delivers it.
+func TestRecallBeforeScan(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	defer func(old bool) { useFakeFiglet = old }(useFakeFiglet)
+	useFakeFiglet = true
+
+	m := initialModel(tuiOptions{})
+	m.termWidth, m.termHeight = 100, 40
+	m, _ = m.recallHistory(historyEntry{Text: "Hi", FontName: "fake-block", FontPath: "/fonts/fake-block.flf", Width: 80, Output: "#H# #i#\n"})
+	if !m.recallPending || m.state != stateOutputChoice {
+		t.Fatalf("recall before the scan: pending %v, state %v", m.recallPending, m.state)
+	}
+
+	fonts := []fontMetadata{
+		{Name: "fake-a", Path: "/fonts/fake-a.flf"},
+		{Name: "fake-block", Path: "/fonts/fake-block.flf", ControlPath: "/fonts/utf8.flc"},
+	}
+	next, _ := m.Update(initialResourcesLoadedMsg{fonts: fonts})
+	m = next.(model)
+	if m.recallPending || m.state != stateOutputChoice {
+		t.Errorf("after the scan: pending %v, state %v, want the output menu still", m.recallPending, m.state)
+	}
+	if m.selectedFontMeta.ControlPath != "/fonts/utf8.flc" {
+		t.Errorf("selected font %+v, want the scanned metadata", m.selectedFontMeta)
+	}
+	if f, ok := m.fontList.SelectedItem().(fontMetadata); !ok || f.Name != "fake-block" {
+		t.Errorf("font list selection %v, want fake-block", m.fontList.SelectedItem())
+	}
+}
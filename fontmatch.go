@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strings"
+)
+
+// --- Fuzzy Font Names ---
+// Font names given to commands (--font and the like) don't have to be exact:
+// a unique case-insensitive, prefix, substring or near-miss match is used,
+// with a note on stderr, and ambiguous names list the candidates. Places
+// where a wrong guess does harm (fonts remove, serve, --stdio) keep exact
+// names with pickFont.
+
+// maxFontSuggestions bounds the candidates listed for an ambiguous name.
+const maxFontSuggestions = 8
+
+// pickFontFuzzy resolves name like pickFont, falling back to fuzzy matching.
+func pickFontFuzzy(fonts []fontMetadata, name string, rng *rand.Rand) (fontMetadata, error) {
+	if font, err := pickFont(fonts, name, rng); err == nil {
+		return font, nil
+	}
+	want := strings.ToLower(name)
+	typos := max(1, len([]rune(want))/4) // "slnat" finds slant, "bg" doesn't find every two-letter font
+	tiers := []struct {
+		how     string
+		matches func(name string) bool
+	}{
+		{"ignoring case", func(n string) bool { return n == want }},
+		{"by prefix", func(n string) bool { return strings.HasPrefix(n, want) }},
+		{"by substring", func(n string) bool { return strings.Contains(n, want) }},
+		{"as a near miss", func(n string) bool { return editDistance(n, want) <= typos }},
+	}
+	for _, tier := range tiers {
+		var found []fontMetadata
+		for _, f := range fonts {
+			if tier.matches(strings.ToLower(f.Name)) {
+				found = append(found, f)
+			}
+		}
+		switch {
+		case len(found) == 1:
+			fmt.Fprintf(os.Stderr, "fontlet: using font %s for %q (matched %s)\n", found[0].Name, name, tier.how)
+			return found[0], nil
+		case len(found) > 1:
+			return fontMetadata{}, fmt.Errorf("font %q is ambiguous, matching %s", name, fontNameList(found))
+		}
+	}
+
+	// Nothing close enough to pick, suggest the nearest few
+	sorted := append([]fontMetadata(nil), fonts...)
+	distance := func(f fontMetadata) int { return editDistance(strings.ToLower(f.Name), want) }
+	sort.SliceStable(sorted, func(i, j int) bool { return distance(sorted[i]) < distance(sorted[j]) })
+	var near []fontMetadata
+	for _, f := range sorted {
+		if len(near) == 3 || distance(f) > len([]rune(want))/2+1 {
+			break
+		}
+		near = append(near, f)
+	}
+	if len(near) > 0 {
+		return fontMetadata{}, fmt.Errorf("unknown font %q, did you mean %s?", name, fontNameList(near))
+	}
+	return fontMetadata{}, fmt.Errorf("unknown font %q (see fontlet fonts list)", name)
+}
+
+// fontNameList lists font names for messages, e.g. "big, bigchief or bigfig".
+func fontNameList(fonts []fontMetadata) string {
+	names := make([]string, 0, min(len(fonts), maxFontSuggestions))
+	for _, f := range fonts[:min(len(fonts), maxFontSuggestions)] {
+		names = append(names, f.Name)
+	}
+	list := strings.Join(names, ", ")
+	if len(fonts) > maxFontSuggestions {
+		return fmt.Sprintf("%s and %d more", list, len(fonts)-maxFontSuggestions)
+	}
+	if i := strings.LastIndex(list, ", "); i >= 0 {
+		list = list[:i] + " or " + list[i+2:]
+	}
+	return list
+}
+
+// editDistance is the Levenshtein distance between a and b, in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// --- Font Aliases ---
+// "font_aliases" in the config gives fonts extra names, e.g. "hero" for
+// ansi_shadow, accepted wherever a font name is and matched by the font list
+// filter. Real font names take precedence over aliases.
+
+// applyFontAliases records the aliases on the fonts they name. Aliases of
+// fonts that aren't installed are left out.
+func applyFontAliases(fonts []fontMetadata, aliases map[string]string) []fontMetadata {
+	byName := make(map[string][]string)
+	for alias, name := range aliases {
+		byName[name] = append(byName[name], alias)
+	}
+	for i := range fonts {
+		fonts[i].Aliases = byName[fonts[i].Name]
+		sort.Strings(fonts[i].Aliases)
+	}
+	return fonts
+}
+
+// validateFontAliases checks the config's aliases.
+func validateFontAliases(aliases map[string]string) error {
+	for alias, name := range aliases {
+		switch {
+		case alias == "" || strings.ContainsAny(alias, " \t\n"):
+			return fmt.Errorf("font alias %q must be a single word", alias)
+		case alias == randomFontName || alias == autoFontName:
+			return fmt.Errorf("font alias %q is reserved for random and auto-fit picks", alias)
+		case name == "":
+			return fmt.Errorf("font alias %q names no font", alias)
+		}
+	}
+	return nil
+}
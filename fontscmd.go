@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+)
+
+// --- Fonts Command ---
+// `fontlet fonts <action>` works on the discovered fonts from scripts.
+
+func runFonts(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		newFlagSet("fonts").Usage()
+		return fmt.Errorf("missing fonts action: grep, import, list, remove, restore or trash")
+	}
+	switch args[0] {
+	case "grep":
+		return runFontsGrep(args[1:], stdout)
+	case "import":
+		return runFontsImport(args[1:], stdout)
+	case "list":
+		return runFontsList(args[1:], stdout)
+	case "remove":
+		return runFontsRemove(args[1:], stdout)
+	case "restore":
+		return runFontsRestore(args[1:], stdout)
+	case "trash":
+		return runFontsTrash(args[1:], stdout)
+	}
+	return fmt.Errorf("unknown fonts action %q, expected grep, import, list, remove, restore or trash", args[0])
+}
+
+// listedFont is a font as `fonts list` describes it to scripts.
+type listedFont struct {
+	Name    string     `json:"name"`
+	Path    string     `json:"path"`
+	Dir     string     `json:"dir"` // The font directory it was found in
+	Height  int        `json:"height"`
+	Charset flfCharset `json:"charset"`
+	Tags    []string   `json:"tags"`
+	Author  string     `json:"author"`            // From the FLF comments, empty if not found
+	License string     `json:"license"`           // Likewise
+	Aliases []string   `json:"aliases,omitempty"` // From font_aliases in the config
+	Control string     `json:"control,omitempty"` // Control file the font needs, see controlfile.go
+}
+
+// fontTags are coarse labels derived from the font file, for filtering with
+// jq or awk: a size class by height, "rtl" for right-to-left fonts, "partial"
+// when printable ASCII isn't fully drawn and "unicode" with characters beyond
+// the required set.
+func fontTags(h flfHeader, cs flfCharset) []string {
+	var tags []string
+	switch {
+	case h.Height == 0:
+		tags = append(tags, "unreadable")
+	case h.Height <= 3:
+		tags = append(tags, "tiny")
+	case h.Height <= 6:
+		tags = append(tags, "small")
+	case h.Height <= 10:
+		tags = append(tags, "medium")
+	default:
+		tags = append(tags, "large")
+	}
+	if h.PrintDirection == 1 {
+		tags = append(tags, "rtl")
+	}
+	if cs.ASCII < 95 {
+		tags = append(tags, "partial")
+	}
+	if cs.Extra > 0 {
+		tags = append(tags, "unicode")
+	}
+	return tags
+}
+
+// runFontsList prints every discovered font with its metadata, as a table
+// for people or JSON/TSV for scripts choosing fonts.
+func runFontsList(args []string, stdout io.Writer) error {
+	fs := newFlagSet("fonts")
+	format := fs.String("format", "text", "output format: text, json, tsv or names (one per line, e.g. for shell completion)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" && *format != "tsv" && *format != "names" {
+		return fmt.Errorf("unknown format %q, expected text, json, tsv or names", *format)
+	}
+
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	sortFontNames(env.fonts, env.cfg.FontSort)
+	fonts := make([]listedFont, len(env.fonts))
+	for i, font := range env.fonts {
+		cs, _ := readFLFCharset(font.Path, font.Header) // Unreadable glyphs just count as missing
+		fonts[i] = listedFont{Name: font.Name, Path: font.Path, Dir: font.Dir, Height: font.Header.Height, Charset: cs, Tags: fontTags(font.Header, cs), Author: font.Credits.Author, License: font.Credits.License, Aliases: font.Aliases, Control: font.Control}
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(fonts)
+	case "tsv":
+		fmt.Fprintln(stdout, "name\tpath\tdir\theight\tascii\tgerman\textra\ttags\tauthor\tlicense")
+		clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+		for _, f := range fonts {
+			fmt.Fprintf(stdout, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\t%s\n", f.Name, f.Path, f.Dir, f.Height, f.Charset.ASCII, f.Charset.German, f.Charset.Extra, strings.Join(f.Tags, ","), clean.Replace(f.Author), clean.Replace(f.License))
+		}
+		return nil
+	case "names":
+		for _, f := range fonts {
+			fmt.Fprintln(stdout, f.Name)
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tHEIGHT\tASCII\tEXTRA\tTAGS\tDIR")
+	for _, f := range fonts {
+		fmt.Fprintf(tw, "%s\t%d\t%d/95\t%d\t%s\t%s\n", f.Name, f.Height, f.Charset.ASCII, f.Charset.Extra, strings.Join(f.Tags, " "), shortenHome(f.Dir))
+	}
+	return tw.Flush()
+}
+
+// runList is `fonts list` under a shorter name, with flags for the common
+// formats; `fontlet --list-fonts` runs it too.
+func runList(args []string, stdout io.Writer) error {
+	fs := newFlagSet("list")
+	asJSON := fs.Bool("json", false, "print JSON: name, path, dir, height, charset, tags, author and license of each font")
+	names := fs.Bool("names", false, "print only the names, one per line, e.g. for shell completion")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("list takes no arguments")
+	}
+	format := "text"
+	switch {
+	case *asJSON && *names:
+		return fmt.Errorf("--json and --names don't go together")
+	case *asJSON:
+		format = "json"
+	case *names:
+		format = "names"
+	}
+	return runFontsList([]string{"--format", format}, stdout)
+}
+
+// grepSampleText is short enough to render on one line of glyphs in any
+// font, so --sample stays a glance rather than a full render.
+const grepSampleText = "AaBb12"
+
+// runFontsGrep lists fonts whose name, path, header or comments (authors,
+// credits, notes) match a regular expression.
+func runFontsGrep(args []string, stdout io.Writer) error {
+	fs := newFlagSet("fonts")
+	ignoreCase := fs.Bool("i", false, "match case-insensitively")
+	sample := fs.Bool("sample", false, "render a short sample ("+grepSampleText+") under each match")
+	width := fs.Int("width", terminalWidth(), "width of the samples in columns")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("fonts grep needs exactly one pattern")
+	}
+	pattern := fs.Arg(0)
+	if *ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	matched := 0
+	for _, font := range env.fonts {
+		comments, _ := readFLFComments(font.Path, font.Header) // Unreadable comments just don't match
+		if !re.MatchString(font.Name) && !re.MatchString(font.Path) && !re.MatchString(font.Header.summary()) && !re.MatchString(comments) {
+			continue
+		}
+		matched++
+		fmt.Fprintf(tw, "%s\t%s\n", font.Name, shortenHome(font.Path))
+		if *sample {
+			tw.Flush() // Samples aren't part of the aligned columns
+			output, err := runFiglet(env.figletCmdPath, font.Path, grepSampleText, *width)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", font.Name, err)
+				continue
+			}
+			fmt.Fprintln(stdout, strings.TrimRight(output, "\n"))
+			fmt.Fprintln(stdout)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if matched == 0 {
+		return fmt.Errorf("no fonts match %q", fs.Arg(0))
+	}
+	return nil
+}
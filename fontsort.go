@@ -0,0 +1,92 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// --- Font Name Order ---
+// Lists show fonts collated: case is ignored and punctuation sorts before
+// digits and letters, so "3-d", "3d_diagonal" and "3x5" come in that order
+// and "Banner" sits next to "banner3". With font_sort "natural", runs of
+// digits also compare by value ("font2" before "font10"); "bytes" is plain
+// byte order, capitals first. Discovery itself stays in byte order, which
+// picks like the banner of the day depend on.
+
+const (
+	fontSortCollate = "collate"
+	fontSortNatural = "natural"
+	fontSortBytes   = "bytes"
+)
+
+// sortFontNames orders fonts by name for display, per font_sort.
+func sortFontNames(fonts []fontMetadata, mode string) {
+	slices.SortStableFunc(fonts, func(a, b fontMetadata) int { return compareFontNames(a.Name, b.Name, mode) })
+}
+
+// compareFontNames compares font names per font_sort, see above. Names
+// equal but for case fall back to byte order, so the order is total.
+func compareFontNames(a, b, mode string) int {
+	if mode == fontSortBytes {
+		return strings.Compare(a, b)
+	}
+	natural := mode == fontSortNatural
+	x, y := a, b
+	for x != "" && y != "" {
+		if natural && isDigit(x[0]) && isDigit(y[0]) {
+			nx, ny := digitRun(x), digitRun(y)
+			if c := compareNumbers(x[:nx], y[:ny]); c != 0 {
+				return c
+			}
+			x, y = x[nx:], y[ny:]
+			continue
+		}
+		rx, sx := utf8.DecodeRuneInString(x)
+		ry, sy := utf8.DecodeRuneInString(y)
+		if c := cmp.Compare(collationClass(rx), collationClass(ry)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(unicode.ToLower(rx), unicode.ToLower(ry)); c != 0 {
+			return c
+		}
+		x, y = x[sx:], y[sy:]
+	}
+	if c := cmp.Compare(len(x), len(y)); c != 0 { // A prefix comes first
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// collationClass puts punctuation and spaces first, then digits, then letters.
+func collationClass(r rune) int {
+	switch {
+	case unicode.IsDigit(r):
+		return 1
+	case unicode.IsLetter(r):
+		return 2
+	}
+	return 0
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// digitRun is the length of the run of ASCII digits s starts with.
+func digitRun(s string) int {
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	return n
+}
+
+// compareNumbers compares runs of digits by value, however long.
+func compareNumbers(x, y string) int {
+	x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+	if c := cmp.Compare(len(x), len(y)); c != 0 {
+		return c
+	}
+	return strings.Compare(x, y)
+}
@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestCompareFontNames(t *testing.T) {
+	tests := []struct {
+		a, b, mode string
+		want       int
+	}{
+		{"3-d", "3d_diagonal", fontSortCollate, -1},
+		{"3d_diagonal", "3x5", fontSortCollate, -1},
+		{"Banner", "banner3", fontSortCollate, -1},
+		{"banner", "Banner", fontSortCollate, 1},
+		{"big", "big", fontSortCollate, 0},
+		{"font10", "font2", fontSortCollate, -1},
+		{"font2", "font10", fontSortNatural, -1},
+		{"font02", "font2", fontSortNatural, -1},
+		{"Zebra", "apple", fontSortBytes, -1},
+		{"Zebra", "apple", fontSortCollate, 1},
+	}
+	for _, tt := range tests {
+		if got := compareFontNames(tt.a, tt.b, tt.mode); got != tt.want {
+			t.Errorf("compareFontNames(%q, %q, %q) = %d, want %d", tt.a, tt.b, tt.mode, got, tt.want)
+		}
+		if got := compareFontNames(tt.b, tt.a, tt.mode); got != -tt.want {
+			t.Errorf("compareFontNames(%q, %q, %q) = %d, want %d", tt.b, tt.a, tt.mode, got, -tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// --- Export Footer ---
+// The "footer" config adds a small block under saved banners, e.g. a
+// timestamp or a handle, plain or rendered in a font. Once configured it's on
+// by default and toggled on the filename screen. Shell script exports
+// regenerate just the banner, so they ask for it to be turned off.
+
+type footerConfig struct {
+	Text  string `json:"text,omitempty"`  // Empty disables the footer; placeholders as in footerVars
+	Font  string `json:"font,omitempty"`  // Render the text in this font, a plain line if empty
+	Align string `json:"align,omitempty"` // Under the banner: left, center or right (default)
+}
+
+// align is where the footer goes under the banner; parseConfig validated it.
+func (c footerConfig) align() layoutAlign {
+	if c.Align == "" {
+		return alignEnd
+	}
+	align, _ := parseLayoutAlign(c.Align)
+	return align
+}
+
+// footerVars are the footer placeholders, the time of the save among them.
+func footerVars(now time.Time) []templateVar {
+	return append([]templateVar{
+		{Name: "date", Desc: "date of the save, e.g. 2024-05-17", Value: func() (string, error) { return now.Format(time.DateOnly), nil }},
+		{Name: "time", Desc: "time of the save, e.g. 14:05", Value: func() (string, error) { return now.Format("15:04"), nil }},
+		{Name: "handle", Desc: "user name", Value: func() (string, error) {
+			u, err := user.Current()
+			if err != nil {
+				return "", err
+			}
+			return u.Username, nil
+		}},
+	}, sysinfoVars()...)
+}
+
+// renderFooter expands the footer text and renders it in its font, if any.
+func renderFooter(cfg footerConfig, fonts []fontMetadata, figletCmdPath string, now time.Time) (string, error) {
+	text, err := expandTemplate(cfg.Text, footerVars(now))
+	if err != nil {
+		return "", err
+	}
+	if cfg.Font == "" {
+		return text, nil
+	}
+	f, err := pickFont(fonts, cfg.Font, newRand(0, false))
+	if err != nil {
+		return "", err
+	}
+	output, err := runFiglet(figletCmdPath, f.Path, text, unwrappedWidth)
+	return trimBanner(output), err
+}
+
+// footerHelp is the footer toggle for the filename screen's help, disabled
+// (left out) when no footer is configured.
+func (m model) footerHelp() key.Binding {
+	if m.cfg.Footer.Text == "" {
+		return key.NewBinding(key.WithDisabled())
+	}
+	return m.keys.ToggleFooter
+}
+
+// centerHelp is the centering toggle for the filename screen's help, disabled
+// (left out) without a center_width.
+func (m model) centerHelp() key.Binding {
+	if m.cfg.CenterWidth <= 0 {
+		return key.NewBinding(key.WithDisabled())
+	}
+	return describe(m.keys.ToggleCenter, fmt.Sprintf("center in %d", m.cfg.CenterWidth))
+}
+
+// withFooter adds the configured footer under banner, keeping the banner's
+// lack of a final newline if it had none.
+func (m model) withFooter(banner string) (string, error) {
+	if !m.saveOpts.Footer || m.cfg.Footer.Text == "" {
+		return banner, nil
+	}
+	footer, err := renderFooter(m.cfg.Footer, m.fonts, m.figletCmdPath, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("footer: %w", err)
+	}
+	composed := composeBlocks([]string{banner, footer}, layoutColumn, m.cfg.Footer.align(), 0)
+	if !strings.HasSuffix(banner, "\n") {
+		composed = strings.TrimSuffix(composed, "\n")
+	}
+	return composed, nil
+}
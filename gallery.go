@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --- Gallery ---
+// `fontlet gallery --out DIR` writes a static HTML font reference: an index
+// with every font's render of a sample text and client-side search, and with
+// --pages a page per font with its metadata and comments. Previews warmed
+// with `fontlet cache warm` for the same text and width are reused, so a
+// gallery of a big collection is quick to regenerate.
+
+type galleryFont struct {
+	Name     string
+	Path     string
+	Summary  string // Header summary, as in `fontlet preview`
+	Tags     string // Space separated, see fontTags
+	Author   string
+	License  string
+	Comments string
+	Render   string
+	Page     string // Relative link to the font's page, empty without --pages
+}
+
+func runGallery(args []string, stdout io.Writer) error {
+	fs := newFlagSet("gallery")
+	out := fs.String("out", "", "directory to write index.html (and with --pages, fonts/NAME.html) to")
+	text := fs.String("text", "Hello", "sample text; use the text you warmed the preview cache with to reuse its renders")
+	width := fs.Int("width", terminalWidth(), "terminal width the previews are rendered for, as with cache warm")
+	pages := fs.Bool("pages", false, "also write a page per font with its metadata and comments")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		fs.Usage()
+		return fmt.Errorf("gallery needs --out")
+	}
+
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	cache := openPreviewCache(env.cfg)
+	renderWidth := previewWidth(*width)
+	fonts := make([]galleryFont, 0, len(env.fonts))
+	cached := 0
+	for _, font := range env.fonts {
+		output, ok := cache.get(previewKey(font, *text, renderWidth, env.figletCmdPath))
+		if ok {
+			cached++
+		} else if output, err = runFiglet(env.figletCmdPath, font.Path, *text, renderWidth); err != nil {
+			fmt.Fprintf(os.Stderr, "skipping %s: %v\n", font.Name, err)
+			continue
+		}
+		cs, _ := readFLFCharset(font.Path, font.Header)
+		comments, _ := readFLFComments(font.Path, font.Header)
+		gf := galleryFont{
+			Name:     font.Name,
+			Path:     shortenHome(font.Path),
+			Summary:  font.Header.summary(),
+			Tags:     strings.Join(fontTags(font.Header, cs), " "),
+			Author:   font.Credits.Author,
+			License:  font.Credits.License,
+			Comments: comments,
+			Render:   stripANSI(output),
+		}
+		if *pages {
+			gf.Page = "fonts/" + font.Name + ".html"
+		}
+		fonts = append(fonts, gf)
+	}
+
+	if err := writeGalleryFile(filepath.Join(*out, "index.html"), env.cfg.fileMode(), galleryIndex, map[string]any{"Text": *text, "Fonts": fonts}); err != nil {
+		return err
+	}
+	if *pages {
+		for _, gf := range fonts {
+			if err := writeGalleryFile(filepath.Join(*out, gf.Page), env.cfg.fileMode(), galleryPage, gf); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Fprintf(stdout, "Wrote a gallery of %d fonts to %s (%d previews from the cache)\n", len(fonts), filepath.Join(*out, "index.html"), cached)
+	return nil
+}
+
+// writeGalleryFile writes a page atomically, so a failed run leaves the
+// previous gallery in place rather than a page cut off partway.
+func writeGalleryFile(path string, perm fs.FileMode, tmpl *template.Template, data any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeAtomic(path, perm, func(f *os.File) error {
+		if err := tmpl.Execute(f, data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+const galleryStyle = `<style>
+  body { font-family: sans-serif; margin: 2em; background: #1e1e2e; color: #cdd6f4; }
+  a { color: #89b4fa; }
+  input { font: inherit; padding: .3em; width: 20em; }
+  .font { border-top: 1px solid #45475a; padding: .5em 0; }
+  .meta { color: #a6adc8; font-size: .9em; }
+  .credits dt { color: #a6adc8; }
+  pre { font-family: monospace; overflow-x: auto; }
+</style>`
+
+var galleryIndex = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Fontlet gallery</title>
+` + galleryStyle + `
+</head>
+<body>
+<h1>{{len .Fonts}} fonts</h1>
+<input id="search" type="search" placeholder="Search names, tags and metadata" autofocus>
+{{range .Fonts}}<div class="font" data-search="{{.Name}} {{.Tags}} {{.Summary}} {{.Path}} {{.Author}} {{.License}}">
+<h2>{{if .Page}}<a href="{{.Page}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</h2>
+<div class="meta">{{.Summary}} · {{.Tags}} · {{.Path}}{{if .Author}} · by {{.Author}}{{end}}</div>
+<pre>{{.Render}}</pre>
+</div>
+{{end}}<script>
+document.getElementById("search").addEventListener("input", e => {
+  const terms = e.target.value.toLowerCase().split(/\s+/).filter(Boolean);
+  for (const el of document.querySelectorAll(".font")) {
+    const text = el.dataset.search.toLowerCase();
+    el.hidden = !terms.every(t => text.includes(t));
+  }
+});
+</script>
+</body>
+</html>
+`))
+
+var galleryPage = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}} · Fontlet gallery</title>
+` + galleryStyle + `
+</head>
+<body>
+<p><a href="../index.html">All fonts</a></p>
+<h1>{{.Name}}</h1>
+<div class="meta">{{.Summary}} · {{.Tags}} · {{.Path}}</div>
+{{if or .Author .License}}<dl class="credits">{{if .Author}}<dt>Author</dt><dd>{{.Author}}</dd>{{end}}{{if .License}}<dt>License</dt><dd>{{.License}}</dd>{{end}}</dl>
+{{end}}<pre>{{.Render}}</pre>
+{{if .Comments}}<h2>Comments</h2>
+<pre>{{.Comments}}</pre>
+{{end}}</body>
+</html>
+`))
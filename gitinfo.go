@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// --- Git Command ---
+// `fontlet git` renders banners from the repository in the current directory,
+// e.g. the release tag in a release script. The same placeholders work in
+// sysinfo templates.
+
+const defaultGitTemplate = "{repo}\n{branch}"
+
+func gitVars() []templateVar {
+	return []templateVar{
+		{Name: "repo", Desc: "repository directory name", Value: func() (string, error) {
+			top, err := gitOutput("rev-parse", "--show-toplevel")
+			return filepath.Base(top), err
+		}},
+		{Name: "branch", Desc: "current branch", Value: func() (string, error) {
+			branch, err := gitOutput("branch", "--show-current")
+			if err == nil && branch == "" {
+				return "", errors.New("detached HEAD, not on a branch")
+			}
+			return branch, err
+		}},
+		{Name: "tag", Desc: "latest tag, e.g. v2.1.0", Value: func() (string, error) { return gitOutput("describe", "--tags", "--abbrev=0") }},
+		{Name: "describe", Desc: "git describe, e.g. v2.1.0-3-gabc1234", Value: func() (string, error) { return gitOutput("describe", "--tags", "--always", "--dirty") }},
+		{Name: "commit", Desc: "short commit hash", Value: func() (string, error) { return gitOutput("rev-parse", "--short", "HEAD") }},
+	}
+}
+
+// gitOutput runs git in the current directory, returning its trimmed output
+// or its error message.
+func gitOutput(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func runGit(args []string, stdout io.Writer) error {
+	vars := gitVars()
+	fs := newFlagSet("git")
+	tmpl := fs.String("template", defaultGitTemplate, `lines to render, each its own block ("\n" separates lines); placeholders: `+placeholderHelp(vars))
+	fontName := fs.String("font", "standard", `font of the blocks ("random" for a random one)`)
+	width := fs.Int("width", terminalWidth(), "output width in columns")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("git takes no arguments")
+	}
+
+	text, err := expandTemplate(strings.ReplaceAll(*tmpl, `\n`, "\n"), vars)
+	if err != nil {
+		return err
+	}
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	font, err := pickFontFuzzy(env.fonts, *fontName, newRand(globalOptions.seed, globalOptions.seeded))
+	if err != nil {
+		return err
+	}
+	return writeStackedBlocks(stdout, env, font, text, *width, imageNone)
+}
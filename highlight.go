@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Render-on-highlight Mode ---
+// With "preview_mode": "highlight" no previews are generated up front. The list
+// shows font names only and the highlighted font is rendered, after a short
+// debounce, into a pane beside it. Renders are kept per font until the text changes.
+
+const (
+	previewModeBulk      = "bulk"
+	previewModeHighlight = "highlight"
+
+	highlightDebounce = 150 * time.Millisecond
+)
+
+type highlightDebounceMsg struct{ seq int }
+type highlightRenderedMsg struct {
+	path, output string
+	target       previewTarget // Text and pane width rendered, stale once either changes
+}
+
+func (m model) highlightMode() bool {
+	return m.cfg.PreviewMode == previewModeHighlight
+}
+
+// fontListWidth leaves room for the preview pane in highlight mode.
+func (m model) fontListWidth() int {
+	w := m.termWidth - docStyle.GetHorizontalFrameSize()
+	if m.highlightMode() {
+		return min(32, w/3)
+	}
+	return w
+}
+
+func (m model) previewPaneWidth() int {
+	return max(m.termWidth-docStyle.GetHorizontalFrameSize()-m.fontListWidth()-2, 0)
+}
+
+// highlightedFont is the list's current selection, if any.
+func (m model) highlightedFont() (fontMetadata, bool) {
+	f, ok := m.fontList.SelectedItem().(fontMetadata)
+	return f, ok
+}
+
+// scheduleHighlightRender debounces rendering so holding an arrow key doesn't
+// spawn a figlet process per font passed.
+func (m *model) scheduleHighlightRender() tea.Cmd {
+	m.highlightSeq++
+	seq := m.highlightSeq
+	return tea.Tick(highlightDebounce, func(time.Time) tea.Msg { return highlightDebounceMsg{seq} })
+}
+
+func (m model) renderHighlightCmd(font fontMetadata) tea.Cmd {
+	target := previewTarget{m.renderText(), m.previewPaneWidth()}
+	return func() tea.Msg {
+		output, err := runFiglet(m.figletCmdPath, font.Path, target.text, max(target.width, 20))
+		if err != nil {
+			output = fmt.Sprintf("Error rendering: %v", err)
+		}
+		return highlightRenderedMsg{path: font.Path, output: output, target: target}
+	}
+}
+
+// highlightPaneView renders the highlighted font beside the list, clipped to fit.
+func (m model) highlightPaneView(height int) string {
+	font, ok := m.highlightedFont()
+	if !ok {
+		return ""
+	}
+	header := fontNameStyle.Render(font.Name) + "  " + sourceStyle.Render(font.sourceAnnotation()+m.usageAnnotation(font))
+	if credits := font.Credits.summary(); credits != "" { // Worth checking before shipping a banner
+		header += "\n" + sourceStyle.Render(truncate(credits, max(m.previewPaneWidth(), 2)))
+		height--
+	}
+
+	body, rendered := m.highlightRenders[font.Path]
+	if !rendered {
+		body = "Rendering..."
+	}
+	lines := strings.Split(body, "\n")
+	if len(lines) > height-2 {
+		lines = lines[:max(height-2, 0)]
+	}
+	pane := renderer.NewStyle().MaxWidth(m.previewPaneWidth())
+	return pane.Render(header + "\n\n" + figletOutputStyle.Render(strings.Join(lines, "\n")))
+}
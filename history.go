@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Output History ---
+// Every full render is kept, newest first, in history.json in the user's state
+// directory (e.g. ~/.local/state/fontlet). Picking an entry from the "recent
+// outputs" screen goes straight to the output choice, without re-rendering.
+
+const defaultHistorySize = 20
+
+type historyEntry struct {
+	Text     string    `json:"text"`
+	FontName string    `json:"font_name"`
+	FontPath string    `json:"font_path"`
+	Filter   string    `json:"filter"`          // Output filter name at render time
+	Width    int       `json:"width,omitempty"` // Narrower re-render width, 0 if it followed the terminal
+	Output   string    `json:"output"`          // Unfiltered figlet output
+	Time     time.Time `json:"time"`
+}
+
+// For list.Item interface
+func (e historyEntry) Title() string { return fmt.Sprintf("%s — %s", e.Text, e.FontName) }
+func (e historyEntry) Description() string {
+	return fmt.Sprintf("%s • filter: %s", e.Time.Local().Format("2006-01-02 15:04"), e.Filter)
+}
+func (e historyEntry) FilterValue() string { return e.Text + " " + e.FontName }
+
+type historySavedMsg struct{ err error }
+
+// stateDir is where fontlet keeps data worth more than a cache, like the history.
+func stateDir() (string, error) {
+	if noState {
+		return "", errNoState
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "fontlet"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "fontlet"), nil
+}
+
+func historyPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.json"), nil
+}
+
+// loadHistory returns the saved entries, newest first. A missing file is an empty history.
+func loadHistory() ([]historyEntry, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, nil // No home directory, run without history
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history %s: %w", path, err)
+	}
+	var entries []historyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid history %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+func saveHistory(entries []historyEntry) error {
+	path, err := historyPath()
+	if errors.Is(err, errNoState) {
+		return nil // Kept in memory for the session only
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeAtomic(path, 0644, func(f *os.File) error {
+		_, err := f.Write(append(data, '\n'))
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to write history %s: %w", path, err)
+	}
+	return nil
+}
+
+func (m model) historySize() int {
+	if m.cfg.HistorySize != 0 {
+		return m.cfg.HistorySize
+	}
+	return defaultHistorySize
+}
+
+// recordHistory adds the current full render as the newest entry, dropping an
+// older identical one and anything past the configured size.
+func (m *model) recordHistory() tea.Cmd {
+	if m.historySize() < 0 { // History disabled
+		return nil
+	}
+	entry := historyEntry{
+		Text:     m.renderText(), // As rendered, recalled without a transform
+		FontName: m.selectedFontMeta.Name,
+		FontPath: m.selectedFontMeta.Path,
+		Filter:   outputFilters[m.filterIndex].Name,
+		Width:    m.renderWidth,
+		Output:   m.fullFigletOutput,
+		Time:     time.Now(),
+	}
+	entries := []historyEntry{entry}
+	for _, e := range m.history {
+		if e.Text == entry.Text && e.FontPath == entry.FontPath && e.Output == entry.Output {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	m.history = entries[:min(len(entries), m.historySize())]
+	snapshot := append([]historyEntry(nil), m.history...)
+	return func() tea.Msg { return historySavedMsg{saveHistory(snapshot)} }
+}
+
+// newHistoryList builds the "recent outputs" picker.
+func (m model) newHistoryList() list.Model {
+	items := make([]list.Item, len(m.history))
+	for i, e := range m.history {
+		items[i] = e
+	}
+	listHeight := m.termHeight - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView()) - 2
+	l := list.New(items, list.NewDefaultDelegate(), m.termWidth-docStyle.GetHorizontalFrameSize(), listHeight)
+	l.Title = "Recent Outputs"
+	l.Styles.Title = listTitleStyle
+	l.Styles.HelpStyle = helpStyle.MarginTop(0)
+	l.Styles.StatusBar = statusMessageStyle.Padding(0, 1)
+	l.SetStatusBarItemName("output", "outputs")
+	l.SetShowHelp(false) // The footer lists the picker's own keys
+	return l
+}
+
+// recallHistory restores an entry and goes to the output choice. Previews of
+// the font list behind it are rendered in the background; before the font scan
+// finished there's no list and Esc goes back to text entry instead.
+func (m model) recallHistory(e historyEntry) (model, tea.Cmd) {
+	m.inputText = e.Text
+	m.transformIndex = 0
+	m.selectedFontMeta = fontMetadata{Name: e.FontName, Path: e.FontPath, Dir: filepath.Dir(e.FontPath)} // Until the scan finds it, see selectRecalledFont
+	m.filterIndex = 0
+	for i, f := range outputFilters {
+		if f.Name == e.Filter {
+			m.filterIndex = i
+		}
+	}
+	m.renderWidth = e.Width
+	m.widthRenders = nil
+	m.fullFigletOutput = e.Output
+	m.logAction("Recalled %q in %s from the recent outputs", e.Text, e.FontName)
+	m.textInput.Blur()
+
+	var cmd tea.Cmd
+	m.recallPending = !m.fontsLoaded
+	if m.fontsLoaded {
+		m, cmd = m.selectRecalledFont()
+	}
+	m = m.toOutputChoice()
+	return m, cmd
+}
+
+// selectRecalledFont takes the recalled font's metadata (control file,
+// header...) from the scan and builds the font list behind the current
+// screen with the font selected, so Esc from the output menu lands on it.
+// A recall before the scan finished runs it once the fonts are loaded.
+func (m model) selectRecalledFont() (model, tea.Cmd) {
+	m.recallPending = false
+	for _, f := range m.fonts {
+		if f.Path == m.selectedFontMeta.Path {
+			m.selectedFontMeta = f
+		}
+	}
+	m.highlightRenders = make(map[string]string)
+	m.showcaseRenders = nil
+	var cmd tea.Cmd
+	if !m.highlightMode() {
+		m, cmd = m.streamPreviews() // Behind the recalled output
+	}
+	state := m.state
+	m = m.enterFontList()
+	m.state = state
+	for i, item := range m.fontList.Items() {
+		if f, ok := item.(fontMetadata); ok && f.Path == m.selectedFontMeta.Path {
+			m.fontList.Select(i)
+		}
+	}
+	return m, cmd
+}
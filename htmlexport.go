@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// --- HTML Export ---
+// The HTML format saves the banner as a <pre> block to paste into a web page
+// or a README that allows HTML. With colors kept, ANSI colors become spans of
+// the same color, so the metal and rainbow filters look as in the terminal.
+
+// exportHTML escapes the banner into a <pre> block, converting ANSI colors to
+// spans; other escape sequences are dropped.
+func exportHTML(banner string) string {
+	banner = strings.TrimSuffix(banner, "\n")
+	var b strings.Builder
+	b.WriteString(`<pre style="line-height: 1.2">`)
+	open := false
+	closeSpan := func() {
+		if open {
+			b.WriteString("</span>")
+			open = false
+		}
+	}
+	last := 0
+	for _, loc := range ansiPattern.FindAllStringIndex(banner, -1) {
+		b.WriteString(html.EscapeString(banner[last:loc[0]]))
+		last = loc[1]
+		seq := banner[loc[0]:loc[1]]
+		if !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "m") {
+			continue // Not a color (SGR) sequence
+		}
+		sgrForeground(strings.Split(seq[2:len(seq)-1], ";"), func(rgb *[3]int, _ bool) {
+			closeSpan()
+			if rgb != nil {
+				fmt.Fprintf(&b, `<span style="color: #%02x%02x%02x">`, rgb[0], rgb[1], rgb[2])
+				open = true
+			}
+		})
+	}
+	b.WriteString(html.EscapeString(banner[last:]))
+	closeSpan()
+	b.WriteString("</pre>\n")
+	return b.String()
+}
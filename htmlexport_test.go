@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestExportHTML(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain\n", "<pre style=\"line-height: 1.2\">plain</pre>\n"},
+		{"<a & b>", "<pre style=\"line-height: 1.2\">&lt;a &amp; b&gt;</pre>\n"},
+		{"\x1b[38;2;255;0;128mhi\x1b[0m", "<pre style=\"line-height: 1.2\"><span style=\"color: #ff0080\">hi</span></pre>\n"},
+		{"\x1b[31mopen", "<pre style=\"line-height: 1.2\"><span style=\"color: #cd0000\">open</span></pre>\n"},
+		{"\x1b[2Kgone", "<pre style=\"line-height: 1.2\">gone</pre>\n"},
+	}
+	for _, tt := range tests {
+		if got := exportHTML(tt.in); got != tt.want {
+			t.Errorf("exportHTML(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
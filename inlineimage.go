@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// --- Inline Images ---
+// clock, timer and sysinfo --image show the banner as the PNG export's image
+// (see pngexport.go) in the cells the text would take, smooth at any font
+// size, in terminals that display images: kitty's graphics protocol (kitty,
+// Ghostty) or iTerm2's inline images (iTerm2, WezTerm). The terminal is
+// recognized from its environment variables, which survive SSH and tmux;
+// inside tmux the images need `set -g allow-passthrough on`.
+
+type imageProtocol int
+
+const (
+	imageNone imageProtocol = iota
+	imageKitty
+	imageITerm2
+)
+
+const imageFlagUsage = "show the banner as an image, in terminals that can (kitty, Ghostty, iTerm2, WezTerm)"
+
+// kittyChunkSize is the most base64 kitty takes in one escape.
+const kittyChunkSize = 4096
+
+// imageFlag is the protocol to draw images with when --image is set,
+// imageNone otherwise.
+func imageFlag(set bool) (imageProtocol, error) {
+	if !set {
+		return imageNone, nil
+	}
+	return terminalImageProtocol()
+}
+
+// terminalImageProtocol is the image protocol of the terminal.
+func terminalImageProtocol() (imageProtocol, error) {
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || strings.Contains(term, "kitty") || term == "xterm-ghostty" || program == "ghostty":
+		return imageKitty, nil
+	case program == "iTerm.app" || os.Getenv("LC_TERMINAL") == "iTerm2" || program == "WezTerm":
+		return imageITerm2, nil
+	}
+	return imageNone, fmt.Errorf("--image needs a terminal that shows images (kitty, Ghostty, iTerm2 or WezTerm)")
+}
+
+// inline is the escape drawing a PNG at the cursor, scaled to cols by rows
+// cells. kitty leaves the cursor where it was; iTerm2 moves it to the image's
+// last line. A nonzero id names a kitty image, which replaces the image
+// drawn before under that id.
+func (p imageProtocol) inline(data []byte, id, cols, rows int) string {
+	if cols == 0 {
+		return "" // Blank, and 0 would ask for the image's own size
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	switch p {
+	case imageKitty:
+		for i := 0; i == 0 || i < len(encoded); i += kittyChunkSize {
+			chunk, more := encoded[i:min(i+kittyChunkSize, len(encoded))], 0
+			if i+kittyChunkSize < len(encoded) {
+				more = 1
+			}
+			if i > 0 {
+				fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+				continue
+			}
+			b.WriteString("\x1b_Ga=T,f=100,C=1,q=2") // q=2: no replies to read back from stdin
+			if id != 0 {
+				fmt.Fprintf(&b, ",i=%d", id)
+			}
+			fmt.Fprintf(&b, ",c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		}
+	case imageITerm2:
+		fmt.Fprintf(&b, "\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0:%s\a", len(data), cols, rows, encoded)
+	}
+	return inTmux(b.String())
+}
+
+// clear is the escape removing kitty's image id. Text written over iTerm2's
+// images replaces them, so they need nothing.
+func (p imageProtocol) clear(id int) string {
+	if p != imageKitty {
+		return ""
+	}
+	return inTmux(fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", id))
+}
+
+// lineAfter moves the cursor from the first line of an image rows high to
+// the start of the line under it.
+func (p imageProtocol) lineAfter(rows int) string {
+	if p == imageKitty {
+		return strings.Repeat("\n", rows)
+	}
+	return "\n"
+}
+
+// inTmux wraps seq in tmux's passthrough when running inside tmux, so it
+// reaches the terminal rather than tmux.
+func inTmux(seq string) string {
+	if os.Getenv("TMUX") == "" {
+		return seq
+	}
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// --- Insert Mode ---
+// `fontlet --insert` is for editor plugins that run fontlet in a terminal and
+// insert the result at the cursor: choosing a font renders it and exits, and
+// the banner is printed between marker lines, so the plugin can find it in
+// whatever the TUI left on stdout. With --out-fifo the bare banner goes to a
+// named pipe instead and stdout carries nothing but the TUI.
+
+const (
+	insertBeginMarker = "<<<FONTLET BEGIN"
+	insertEndMarker   = "<<<FONTLET END>>>"
+)
+
+// writeInsertion writes the final render of an insert mode session, plain
+// text and filtered like it would be saved.
+func writeInsertion(m model, stdout io.Writer, fifo string) error {
+	banner := stripANSI(m.outputText())
+	if fifo != "" {
+		f, err := os.OpenFile(fifo, os.O_WRONLY, 0) // Blocks until the plugin opens the reading end
+		if err != nil {
+			return fmt.Errorf("failed to open --out-fifo: %w", err)
+		}
+		if _, err := io.WriteString(f, banner); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write --out-fifo: %w", err)
+		}
+		return f.Close()
+	}
+	lines := bannerLines(banner)
+	_, err := fmt.Fprintf(stdout, "%s font=%s lines=%d>>>\n%s\n%s\n", insertBeginMarker, m.selectedFontMeta.Name, len(lines), strings.Join(lines, "\n"), insertEndMarker)
+	return err
+}
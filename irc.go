@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --- IRC Export ---
+// IRC clients don't understand ANSI escapes but have their own mIRC color
+// codes: \x03 followed by a color number out of a 16-color palette. The IRC
+// export format translates the colors of the saved banner (with colors kept)
+// to the nearest palette entries, like `toilet --irc` does.
+
+// mircPalette is the RGB of each mIRC color number, as mIRC draws them.
+var mircPalette = [16][3]int{
+	{255, 255, 255}, {0, 0, 0}, {0, 0, 127}, {0, 147, 0},
+	{255, 0, 0}, {127, 0, 0}, {156, 0, 156}, {252, 127, 0},
+	{255, 255, 0}, {0, 252, 0}, {0, 147, 147}, {0, 255, 255},
+	{0, 0, 252}, {255, 0, 255}, {127, 127, 127}, {210, 210, 210},
+}
+
+const (
+	ircColor = "\x03"
+	ircReset = "\x0f"
+	// ircSeparator is bold on and off again, nothing on screen, to end a
+	// color code that a "," or digit of the banner would otherwise extend.
+	ircSeparator = "\x02\x02"
+)
+
+// exportIRC converts ANSI colors to mIRC codes; everything else is dropped.
+func exportIRC(banner string) string {
+	var out strings.Builder
+	last := 0
+	for _, loc := range ansiPattern.FindAllStringIndex(banner, -1) {
+		out.WriteString(banner[last:loc[0]])
+		last = loc[1]
+		seq := banner[loc[0]:loc[1]]
+		if !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "m") {
+			continue // Not a color (SGR) sequence
+		}
+		code := sgrToIRC(strings.Split(seq[2:len(seq)-1], ";"))
+		out.WriteString(code)
+		if strings.HasSuffix(code, ircReset) || code == "" {
+			continue
+		}
+		if next := nextText(banner[last:]); next == ',' || (next >= '0' && next <= '9') {
+			out.WriteString(ircSeparator)
+		}
+	}
+	out.WriteString(banner[last:])
+	return out.String()
+}
+
+// nextText is the first byte of s that isn't part of an escape sequence, 0
+// if there is none.
+func nextText(s string) byte {
+	for s != "" {
+		loc := ansiPattern.FindStringIndex(s)
+		if loc == nil || loc[0] > 0 {
+			return s[0]
+		}
+		s = s[loc[1]:]
+	}
+	return 0
+}
+
+// sgrToIRC translates SGR parameters: resets and foreground colors.
+func sgrToIRC(params []string) string {
+	var out strings.Builder
+	sgrForeground(params, func(rgb *[3]int, reset bool) {
+		switch {
+		case reset:
+			out.WriteString(ircReset)
+		case rgb == nil:
+			out.WriteString(ircColor) // A bare \x03 restores the default color
+		default:
+			out.WriteString(ircColorCode(*rgb))
+		}
+	})
+	return out.String()
+}
+
+// sgrForeground walks SGR parameters for their foreground colors, in the
+// 8/16 color, 256 color and truecolor forms. fg gets each color set, or nil
+// where the default color comes back, with reset for a full reset (0)
+// rather than the color's alone (39).
+func sgrForeground(params []string, fg func(rgb *[3]int, reset bool)) {
+	color := func(rgb [3]int) { fg(&rgb, false) }
+	for i := 0; i < len(params); i++ {
+		n, err := strconv.Atoi(params[i])
+		if err != nil && params[i] != "" {
+			continue
+		}
+		switch {
+		case n == 0:
+			fg(nil, true)
+		case n == 39:
+			fg(nil, false)
+		case n >= 30 && n <= 37:
+			color(ansi256RGB(n - 30))
+		case n >= 90 && n <= 97:
+			color(ansi256RGB(n - 90 + 8))
+		case n == 38 && i+2 < len(params) && params[i+1] == "5":
+			c, _ := strconv.Atoi(params[i+2])
+			color(ansi256RGB(c))
+			i += 2
+		case n == 38 && i+4 < len(params) && params[i+1] == "2":
+			var rgb [3]int
+			for j := range rgb {
+				rgb[j], _ = strconv.Atoi(params[i+2+j])
+			}
+			color(rgb)
+			i += 4
+		case (n == 48 || n == 58) && i+1 < len(params): // Skip background and underline colors' arguments
+			if params[i+1] == "5" {
+				i += 2
+			} else if params[i+1] == "2" {
+				i += 4
+			}
+		}
+	}
+}
+
+// ircColorCode picks the nearest palette color. The number is always two
+// digits, so a digit after it can't extend the number, but "," and a digit
+// would still read as a background color; exportIRC separates those.
+func ircColorCode(rgb [3]int) string {
+	best, bestDist := 0, -1
+	for i, p := range mircPalette {
+		dist := 0
+		for j := range p {
+			d := p[j] - rgb[j]
+			dist += d * d
+		}
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return fmt.Sprintf("%s%02d", ircColor, best)
+}
+
+// ansi256RGB is the RGB of a 256-color palette entry, with xterm's defaults
+// for the first 16.
+func ansi256RGB(c int) [3]int {
+	basic := [16][3]int{
+		{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+		{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+		{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+		{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+	switch {
+	case c < 0 || c > 255:
+		return [3]int{255, 255, 255}
+	case c < 16:
+		return basic[c]
+	case c < 232: // 6x6x6 cube
+		c -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + 40*v
+		}
+		return [3]int{level(c / 36), level(c / 6 % 6), level(c % 6)}
+	}
+	g := 8 + 10*(c-232) // Grayscale ramp
+	return [3]int{g, g, g}
+}
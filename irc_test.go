@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestIRCColorCode(t *testing.T) {
+	tests := []struct {
+		rgb  [3]int
+		want string
+	}{
+		{[3]int{255, 255, 255}, "\x0300"},
+		{[3]int{0, 0, 0}, "\x0301"},
+		{[3]int{250, 10, 10}, "\x0304"},
+		{[3]int{0, 250, 0}, "\x0309"},
+		{[3]int{128, 128, 128}, "\x0314"},
+	}
+	for _, tt := range tests {
+		if got := ircColorCode(tt.rgb); got != tt.want {
+			t.Errorf("ircColorCode(%v) = %q, want %q", tt.rgb, got, tt.want)
+		}
+	}
+}
+
+func TestExportIRC(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain", "plain"},
+		{"\x1b[31mred\x1b[0m", "\x0304red\x0f"},
+		{"\x1b[31m,5\x1b[0m", "\x0304\x02\x02,5\x0f"},
+		{"\x1b[31mx\x1b[39m7", "\x0304x\x03\x02\x027"},
+		{"\x1b[31m\x1b[1m9", "\x0304\x02\x029"},
+		{"\x1b[0m1", "\x0f1"},
+		{"\x1b[2Kgone", "gone"},
+	}
+	for _, tt := range tests {
+		if got := exportIRC(tt.in); got != tt.want {
+			t.Errorf("exportIRC(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// --- Keybindings ---
+// Application actions, remappable through the "keys" section of the config.
+// Dialog answers like (y/n) and the font list's own navigation aren't remappable.
+
+type keyMap struct {
+	Quit           key.Binding
+	Suspend        key.Binding
+	Confirm        key.Binding
+	Back           key.Binding
+	CloseView      key.Binding
+	EditFont       key.Binding
+	Rescan         key.Binding
+	ReloadConfig   key.Binding
+	CycleFilter    key.Binding
+	OutputTerminal key.Binding
+	OutputFile     key.Binding
+	OutputCompose  key.Binding
+	OutputCopy     key.Binding
+	OutputPrint    key.Binding
+	CycleFormat    key.Binding
+	ToggleColors   key.Binding
+	ToggleCRLF     key.Binding
+	ToggleBOM      key.Binding
+	ToggleFooter   key.Binding
+	ToggleCenter   key.Binding
+	Snippets       key.Binding
+	SnippetAdd     key.Binding
+	SnippetDelete  key.Binding
+	RandomFont     key.Binding
+	Showcase       key.Binding
+	SortUsage      key.Binding
+	ShowcasePrev   key.Binding
+	ShowcaseNext   key.Binding
+	History        key.Binding
+	ToggleLog      key.Binding
+	CycleWidth     key.Binding
+	AutoFit        key.Binding
+	EditText       key.Binding
+	PurgeCache     key.Binding
+	Transforms     key.Binding
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Quit:           key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
+		Suspend:        key.NewBinding(key.WithKeys("ctrl+z"), key.WithHelp("ctrl+z", "suspend")),
+		Confirm:        key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "confirm")),
+		Back:           key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+		CloseView:      key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc/q", "back to font list")),
+		EditFont:       key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit font")),
+		Rescan:         key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "rescan")),
+		ReloadConfig:   key.NewBinding(key.WithKeys("f5"), key.WithHelp("f5", "reload config")),
+		CycleFilter:    key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "cycle filter")),
+		OutputTerminal: key.NewBinding(key.WithKeys("t", "T"), key.WithHelp("t", "terminal")),
+		OutputFile:     key.NewBinding(key.WithKeys("f", "F"), key.WithHelp("f", "file")),
+		OutputCompose:  key.NewBinding(key.WithKeys("c", "C"), key.WithHelp("c", "compose")),
+		OutputCopy:     key.NewBinding(key.WithKeys("y", "Y"), key.WithHelp("y", "clipboard")),
+		OutputPrint:    key.NewBinding(key.WithKeys("p", "P"), key.WithHelp("p", "print")),
+		CycleFormat:    key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "cycle format")),
+		ToggleColors:   key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "colors")),
+		ToggleCRLF:     key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "CRLF")),
+		ToggleBOM:      key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "BOM")),
+		ToggleFooter:   key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("ctrl+g", "footer")),
+		ToggleCenter:   key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "center")),
+		Snippets:       key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "snippets")),
+		SnippetAdd:     key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add current text")),
+		SnippetDelete:  key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "delete")),
+		RandomFont:     key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "random font")),
+		Showcase:       key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "showcase")),
+		SortUsage:      key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "sort by use")),
+		ShowcasePrev:   key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←", "previous font")),
+		ShowcaseNext:   key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→", "next font")),
+		History:        key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "recent outputs")),
+		ToggleLog:      key.NewBinding(key.WithKeys("f2"), key.WithHelp("f2", "session log")),
+		CycleWidth:     key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "cycle width")),
+		AutoFit:        key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "auto-fit")),
+		EditText:       key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "edit text")),
+		PurgeCache:     key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "purge cache")),
+		Transforms:     key.NewBinding(key.WithKeys("f3"), key.WithHelp("f3", "transforms")),
+	}
+}
+
+type namedBinding struct {
+	Name    string // Action name used in the config file
+	Binding *key.Binding
+}
+
+// named lists the bindings in display order with their config names.
+func (k *keyMap) named() []namedBinding {
+	return []namedBinding{
+		{"quit", &k.Quit},
+		{"suspend", &k.Suspend},
+		{"confirm", &k.Confirm},
+		{"back", &k.Back},
+		{"close_view", &k.CloseView},
+		{"edit_font", &k.EditFont},
+		{"rescan", &k.Rescan},
+		{"reload_config", &k.ReloadConfig},
+		{"cycle_filter", &k.CycleFilter},
+		{"output_terminal", &k.OutputTerminal},
+		{"output_file", &k.OutputFile},
+		{"output_compose", &k.OutputCompose},
+		{"output_copy", &k.OutputCopy},
+		{"output_print", &k.OutputPrint},
+		{"cycle_format", &k.CycleFormat},
+		{"toggle_colors", &k.ToggleColors},
+		{"toggle_crlf", &k.ToggleCRLF},
+		{"toggle_bom", &k.ToggleBOM},
+		{"toggle_footer", &k.ToggleFooter},
+		{"toggle_center", &k.ToggleCenter},
+		{"snippets", &k.Snippets},
+		{"snippet_add", &k.SnippetAdd},
+		{"snippet_delete", &k.SnippetDelete},
+		{"random_font", &k.RandomFont},
+		{"showcase", &k.Showcase},
+		{"sort_usage", &k.SortUsage},
+		{"showcase_prev", &k.ShowcasePrev},
+		{"showcase_next", &k.ShowcaseNext},
+		{"history", &k.History},
+		{"toggle_log", &k.ToggleLog},
+		{"cycle_width", &k.CycleWidth},
+		{"auto_fit", &k.AutoFit},
+		{"edit_text", &k.EditText},
+		{"purge_cache", &k.PurgeCache},
+		{"transforms", &k.Transforms},
+	}
+}
+
+// newKeyMap applies config overrides (action name -> keys) to the defaults.
+func newKeyMap(overrides map[string][]string) (keyMap, error) {
+	k := defaultKeyMap()
+	bindings := make(map[string]*key.Binding)
+	for _, nb := range k.named() {
+		bindings[nb.Name] = nb.Binding
+	}
+	for name, keys := range overrides {
+		b, ok := bindings[name]
+		if !ok {
+			return k, fmt.Errorf("unknown key action %q in config", name)
+		}
+		if len(keys) == 0 {
+			return k, fmt.Errorf("no keys given for action %q in config", name)
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+	return k, nil
+}
+
+// describe returns a copy of b with a context-specific help description.
+func describe(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// helpView renders bindings as a footer help line, e.g. "enter: confirm • ctrl+c: quit".
+// Bindings without a key are shown as plain notes, disabled ones are left out.
+func helpView(bindings ...key.Binding) string {
+	parts := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		if b.Help().Key == "" {
+			parts = append(parts, b.Help().Desc)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", b.Help().Key, b.Help().Desc))
+	}
+	return helpStyle.Render(strings.Join(parts, " • "))
+}
+
+// infoBinding documents keys handled by a bubbles component (e.g. list navigation)
+// so they can appear in help lines.
+func infoBinding(keys, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys), key.WithHelp(keys, desc))
+}
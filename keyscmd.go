@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// --- Keys Command ---
+// `fontlet keys` prints the effective keymap, with the config's remaps
+// applied, straight from the keyMap so the cheat sheet can't go stale.
+
+func runKeys(args []string, stdout io.Writer) error {
+	fs := newFlagSet("keys")
+	format := fs.String("format", "text", "output format: text or md (a Markdown table)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("keys takes no arguments")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	keys, err := newKeyMap(cfg.Keys)
+	if err != nil {
+		return err
+	}
+	defaults := defaultKeyMap()
+
+	switch *format {
+	case "md":
+		return writeKeysMarkdown(stdout, keys.named(), defaults.named())
+	case "text":
+		tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		for i, nb := range keys.named() {
+			fmt.Fprintf(tw, "%s\t%s\t%s%s\n", nb.Name, strings.Join(nb.Binding.Keys(), " "), nb.Binding.Help().Desc, remappedNote(nb, defaults.named()[i]))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown format %q, expected text or md", *format)
+}
+
+func writeKeysMarkdown(w io.Writer, bindings, defaults []namedBinding) error {
+	var b strings.Builder
+	b.WriteString("| Action | Keys | Description |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	for i, nb := range bindings {
+		keys := make([]string, len(nb.Binding.Keys()))
+		for j, k := range nb.Binding.Keys() {
+			keys[j] = markdownCode(k)
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s%s |\n", nb.Name, strings.Join(keys, ", "),
+			markdownEscape(nb.Binding.Help().Desc), remappedNote(nb, defaults[i]))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// remappedNote marks bindings the config changed from the defaults.
+func remappedNote(nb, def namedBinding) string {
+	if slices.Equal(nb.Binding.Keys(), def.Binding.Keys()) {
+		return ""
+	}
+	return fmt.Sprintf(" (remapped, default %s)", strings.Join(def.Binding.Keys(), "/"))
+}
+
+// markdownCode wraps s in a code span that survives backticks and pipes in
+// keys like "`" or "|".
+func markdownCode(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Layout ---
+// Rendered blocks are composed into one document by stacking them in a
+// column or placing them side by side in a row, aligned and spaced. Blocks
+// keep their shape: each is padded to its widest line before it's placed, so
+// a centered block doesn't get its lines centered one by one. The TUI's
+// composition screen and `fontlet compose` both lay out with composeBlocks.
+
+type layoutDirection string
+
+const (
+	layoutColumn layoutDirection = "column" // Top to bottom
+	layoutRow    layoutDirection = "row"    // Left to right
+)
+
+// layoutAlign places blocks across the direction: left, center or right in
+// a column, top, middle or bottom in a row.
+type layoutAlign string
+
+const (
+	alignStart  layoutAlign = "start"
+	alignCenter layoutAlign = "center"
+	alignEnd    layoutAlign = "end"
+)
+
+// parseLayoutAlign accepts the generic names and the ones matching a direction.
+func parseLayoutAlign(s string) (layoutAlign, error) {
+	switch s {
+	case "", "start", "left", "top":
+		return alignStart, nil
+	case "center", "middle":
+		return alignCenter, nil
+	case "end", "right", "bottom":
+		return alignEnd, nil
+	}
+	return "", fmt.Errorf("unknown alignment %q, expected left, center or right (top, middle or bottom in rows)", s)
+}
+
+// blockShape splits a banner into lines and returns its width in columns.
+func blockShape(banner string) ([]string, int) {
+	if banner == "" {
+		return nil, 0
+	}
+	lines := bannerLines(banner)
+	width := 0
+	for _, line := range lines {
+		width = max(width, lipgloss.Width(line))
+	}
+	return lines, width
+}
+
+// offset is where a span of size n starts within total, for the alignment.
+func (a layoutAlign) offset(n, total int) int {
+	switch a {
+	case alignCenter:
+		return (total - n) / 2
+	case alignEnd:
+		return total - n
+	}
+	return 0
+}
+
+// composeBlocks lays the blocks out in one document, with spacing blank lines
+// (column) or columns (row) between them. Trailing spaces are trimmed.
+func composeBlocks(blocks []string, direction layoutDirection, align layoutAlign, spacing int) string {
+	aligns := make([]layoutAlign, len(blocks))
+	for i := range aligns {
+		aligns[i] = align
+	}
+	return composeAligned(blocks, aligns, direction, spacing)
+}
+
+// composeAligned is composeBlocks with an alignment per block, e.g. a
+// left-aligned title over a right-aligned signature, which figlet can't do
+// across fonts of different sizes.
+func composeAligned(blocks []string, aligns []layoutAlign, direction layoutDirection, spacing int) string {
+	type shape struct {
+		lines []string
+		width int
+	}
+	shapes := make([]shape, len(blocks))
+	maxWidth, maxHeight := 0, 0
+	for i, b := range blocks {
+		shapes[i].lines, shapes[i].width = blockShape(b)
+		maxWidth = max(maxWidth, shapes[i].width)
+		maxHeight = max(maxHeight, len(shapes[i].lines))
+	}
+	pad := func(line string, width int) string {
+		return line + strings.Repeat(" ", max(width-lipgloss.Width(line), 0))
+	}
+
+	var out []string
+	if direction == layoutRow {
+		out = make([]string, maxHeight)
+		for i, s := range shapes {
+			if i > 0 {
+				for y := range out {
+					out[y] += strings.Repeat(" ", spacing)
+				}
+			}
+			top := aligns[i].offset(len(s.lines), maxHeight)
+			for y := range out {
+				line := ""
+				if y >= top && y-top < len(s.lines) {
+					line = s.lines[y-top]
+				}
+				out[y] += pad(line, s.width)
+			}
+		}
+	} else {
+		for i, s := range shapes {
+			if i > 0 {
+				out = append(out, make([]string, spacing)...)
+			}
+			indent := strings.Repeat(" ", aligns[i].offset(s.width, maxWidth))
+			for _, line := range s.lines {
+				out = append(out, indent+pad(line, s.width))
+			}
+		}
+	}
+	for i, line := range out {
+		out[i] = strings.TrimRight(line, " ")
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// centerBlock centers a banner as a block within width columns, e.g. for a
+// README whose width differs from the terminal's. Trailing spaces don't count
+// towards the banner's width. Wider banners are unchanged.
+func centerBlock(banner string, width int) string {
+	lines, _ := blockShape(banner)
+	blockWidth := 0
+	for _, line := range lines {
+		blockWidth = max(blockWidth, lipgloss.Width(strings.TrimRight(stripANSI(line), " ")))
+	}
+	if blockWidth >= width {
+		return banner
+	}
+	indent := strings.Repeat(" ", alignCenter.offset(blockWidth, width))
+	for i, line := range lines {
+		if strings.TrimSpace(stripANSI(line)) != "" {
+			lines[i] = indent + line
+		}
+	}
+	centered := strings.Join(lines, "\n")
+	if strings.HasSuffix(banner, "\n") {
+		centered += "\n"
+	}
+	return centered
+}
+
+// --- Compose Command ---
+// `fontlet compose FILE` renders a composition described in JSON, e.g.
+//
+//	{"align": "center", "spacing": 1, "children": [
+//	  {"font": "big", "text": "{repo}"},
+//	  {"divider": "─"},
+//	  {"font": "small", "text": "{branch}", "align_self": "right"}
+//	]}
+//
+// Nodes render text in a font, draw a divider or lay out their children.
+// Text takes the placeholders of `fontlet sysinfo` and `fontlet git`.
+// Dividers span the widest of their siblings unless given a width. A node's
+// align_self places it differently from the rest of its siblings.
+
+type layoutNode struct {
+	Font      string       `json:"font,omitempty"`       // Default: the parent's font, else standard
+	Text      string       `json:"text,omitempty"`       // Rendered in Font; a node has Text, Divider or Children
+	Divider   string       `json:"divider,omitempty"`    // Pattern of a rule, rendered in Font only if set on this node
+	Width     int          `json:"width,omitempty"`      // Of a divider
+	Direction string       `json:"direction,omitempty"`  // column (default) or row
+	Align     string       `json:"align,omitempty"`      // Of the children
+	AlignSelf string       `json:"align_self,omitempty"` // Of this node among its siblings, default the parent's align
+	Spacing   int          `json:"spacing,omitempty"`    // Blank lines (column) or columns (row) between children
+	Children  []layoutNode `json:"children,omitempty"`
+}
+
+// layoutEnv is what rendering a composition needs besides the nodes.
+type layoutEnv struct {
+	cli   cliEnv
+	vars  []templateVar
+	width int // Text wider than this wraps, as in other commands
+}
+
+// render renders the node's text or divider, or lays out its children.
+// Dividers without a width are span columns wide.
+func (n layoutNode) render(env layoutEnv, inheritedFont string, span int) (string, error) {
+	font := n.Font
+	if font == "" {
+		font = inheritedFont
+	}
+	kinds := 0
+	for _, set := range []bool{n.Text != "", n.Divider != "", len(n.Children) > 0} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds > 1 {
+		return "", fmt.Errorf("a node has one of text, divider or children")
+	}
+	if n.Divider != "" {
+		width := span
+		if n.Width > 0 {
+			width = n.Width
+		}
+		if n.Font == "" {
+			return makeDivider(n.Divider, width), nil
+		}
+		f, err := pickFontFuzzy(env.cli.fonts, n.Font, newRand(globalOptions.seed, globalOptions.seeded))
+		if err != nil {
+			return "", err
+		}
+		return renderedDivider(env.cli.figletCmdPath, f.Path, n.Divider, width)
+	}
+	if n.Text != "" {
+		text, err := expandTemplate(n.Text, env.vars)
+		if err != nil {
+			return "", err
+		}
+		f, err := pickFontFor(env.cli, font, text, max(span, 1), newRand(globalOptions.seed, globalOptions.seeded))
+		if err != nil {
+			return "", err
+		}
+		output, err := runFiglet(env.cli.figletCmdPath, f.Path, text, env.width)
+		if err != nil {
+			return "", err
+		}
+		return trimBanner(output), nil
+	}
+	if len(n.Children) == 0 {
+		return "", fmt.Errorf("a node needs text, a divider or children")
+	}
+
+	direction := layoutDirection(n.Direction)
+	if direction == "" {
+		direction = layoutColumn
+	}
+	if direction != layoutColumn && direction != layoutRow {
+		return "", fmt.Errorf("unknown direction %q, expected column or row", n.Direction)
+	}
+	align, err := parseLayoutAlign(n.Align)
+	if err != nil {
+		return "", err
+	}
+	if n.Spacing < 0 {
+		return "", fmt.Errorf("negative spacing %d", n.Spacing)
+	}
+	blocks := make([]string, len(n.Children))
+	// Dividers go last, to span the widest of the other children
+	widest := 0
+	for _, dividers := range []bool{false, true} {
+		if dividers && widest == 0 { // Only dividers, span the parent's span
+			widest = span
+		}
+		for i, child := range n.Children {
+			if (child.Divider != "") != dividers {
+				continue
+			}
+			childSpan := span // Nested dividers alone span what this node spans
+			if dividers {
+				childSpan = widest
+			}
+			if blocks[i], err = child.render(env, font, childSpan); err != nil {
+				return "", fmt.Errorf("child %d: %w", i+1, err)
+			}
+			if !dividers {
+				widest = max(widest, measureBanner(blocks[i]).Width)
+			}
+		}
+	}
+	aligns := make([]layoutAlign, len(n.Children))
+	for i, child := range n.Children {
+		if aligns[i] = align; child.AlignSelf != "" {
+			if aligns[i], err = parseLayoutAlign(child.AlignSelf); err != nil {
+				return "", fmt.Errorf("child %d: %w", i+1, err)
+			}
+		}
+	}
+	return composeAligned(blocks, aligns, direction, n.Spacing), nil
+}
+
+func runCompose(args []string, stdout io.Writer) error {
+	fs := newFlagSet("compose")
+	width := fs.Int("width", terminalWidth(), "width at which each text block wraps")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("compose needs exactly one composition file (- for stdin)")
+	}
+
+	var data []byte
+	var err error
+	if fs.Arg(0) == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(expandHome(fs.Arg(0)))
+	}
+	if err != nil {
+		return err
+	}
+	var root layoutNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("invalid composition %s: %w", fs.Arg(0), err)
+	}
+	cli, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	env := layoutEnv{cli: cli, vars: append(sysinfoVars(), gitVars()...), width: *width}
+	output, err := root.render(env, "standard", *width)
+	if err != nil {
+		return fmt.Errorf("invalid composition %s: %w", fs.Arg(0), err)
+	}
+	_, err = io.WriteString(stdout, figletOutputStyle.Render(strings.TrimSuffix(output, "\n"))+"\n")
+	return err
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Measure ---
+// `fontlet measure` reports the size of a render instead of printing it, for
+// scripts checking whether a banner fits before showing it. figlet does the
+// kerning and smushing, so the size is exact for the font rather than an
+// estimate from its header.
+
+// unwrappedWidth is passed to figlet when measuring text laid out on one line.
+const unwrappedWidth = 1 << 16
+
+// bannerSize is how much screen a banner takes.
+type bannerSize struct {
+	Width  int `json:"width"`  // Terminal columns of the widest line, colors ignored
+	Height int `json:"height"` // Lines, blank descender rows included
+}
+
+// measureBanner returns the size of a rendered banner.
+func measureBanner(banner string) bannerSize {
+	lines := bannerLines(stripANSI(banner))
+	size := bannerSize{Height: len(lines)}
+	if banner == "" {
+		size.Height = 0
+	}
+	for _, line := range lines {
+		size.Width = max(size.Width, lipgloss.Width(line))
+	}
+	return size
+}
+
+// measureText renders text in the font and returns its size. A width of 0
+// measures it unwrapped, as it would need to be shown on one line.
+func measureText(figletCmdPath, fontPath, text string, width int) (bannerSize, error) {
+	if width <= 0 {
+		width = unwrappedWidth
+	}
+	output, err := runFiglet(figletCmdPath, fontPath, text, width)
+	if err != nil {
+		return bannerSize{}, err
+	}
+	return measureBanner(output), nil
+}
+
+func runMeasure(args []string, stdout io.Writer) error {
+	fs := newFlagSet("measure")
+	fontName := fs.String("font", "standard", `font to measure with ("random" for a random one, "auto" for the biggest fitting --max-width, else --width or the terminal)`)
+	width := fs.Int("width", 0, "wrap at this many columns, as rendering would (default: unwrapped)")
+	maxWidth := fs.Int("max-width", 0, "fail if the render is wider than this, for fit checks")
+	format := fs.String("format", "text", `output format: text ("WIDTH HEIGHT") or json`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("measure needs the text to measure")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q, expected text or json", *format)
+	}
+
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	text := strings.Join(fs.Args(), " ")
+	fitWidth := *maxWidth // What "auto" fits
+	if fitWidth <= 0 {
+		fitWidth = cmp.Or(*width, terminalWidth())
+	}
+	font, err := pickFontFor(env, *fontName, text, fitWidth, newRand(globalOptions.seed, globalOptions.seeded))
+	if err != nil {
+		return err
+	}
+	size, err := measureText(env.figletCmdPath, font.Path, text, *width)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(struct {
+			Font string `json:"font"`
+			bannerSize
+		}{font.Name, size})
+	} else {
+		_, err = fmt.Fprintf(stdout, "%d %d\n", size.Width, size.Height)
+	}
+	if err != nil {
+		return err
+	}
+	if *maxWidth > 0 && size.Width > *maxWidth {
+		return fmt.Errorf("%s renders %d columns wide, over the %d column limit", font.Name, size.Width, *maxWidth)
+	}
+	return nil
+}
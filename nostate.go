@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// --- Stateless Mode ---
+// `fontlet --no-state` (or FONTLET_NO_STATE=1) reads and writes nothing
+// under the user's config, cache and state dirs, so containers and CI images
+// need no writable home: the config comes from $FONTLET_CONFIG (the JSON of
+// config.json) and falls back to the defaults, history only lasts the
+// session, and the font index and preview cache are skipped. Files the user
+// asks for, like saved banners or serve's --cache-dir, are still written.
+
+const (
+	noStateEnv = "FONTLET_NO_STATE"
+	configEnv  = "FONTLET_CONFIG"
+)
+
+var noState bool
+
+var errNoState = errors.New("nothing is kept on disk with --no-state")
+
+// noStateFromEnv is the default of --no-state. FONTLET_NO_STATE takes the
+// values strconv.ParseBool does (1, true, 0, false...); anything else leaves
+// it off with a warning, rather than turning it on for FONTLET_NO_STATE=0.
+func noStateFromEnv() bool {
+	value := os.Getenv(noStateEnv)
+	if value == "" {
+		return false
+	}
+	on, err := strconv.ParseBool(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fontlet: ignoring %s=%q, expected 1 or 0 (true or false)\n", noStateEnv, value)
+		return false
+	}
+	return on
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestNoStateFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"1", true},
+		{"true", true},
+		{"TRUE", true},
+		{"0", false},
+		{"false", false},
+		{"no", false}, // Invalid, warned about
+		{"yes", false},
+	}
+	for _, tt := range tests {
+		t.Setenv(noStateEnv, tt.value)
+		if got := noStateFromEnv(); got != tt.want {
+			t.Errorf("noStateFromEnv() with %s=%q = %v, want %v", noStateEnv, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Desktop Notifications ---
+// `fontlet timer --notify` runs a notification hook when time is up, with a
+// small rendered banner as the message text. The hook is notify_command from
+// the config, or notify-send (osascript on macOS) by default.
+
+const defaultNotifyFont = "small"
+
+type notifyDoneMsg struct{ err error }
+
+// notifyArgv is the hook command with {title} and {banner} filled in.
+func notifyArgv(cfg config, title, banner string) ([]string, error) {
+	template := cfg.NotifyCommand
+	if len(template) == 0 {
+		switch runtime.GOOS {
+		case "darwin":
+			template = []string{"osascript", "-e", `display notification "{banner}" with title "{title}"`}
+			quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`) // AppleScript string literal
+			title, banner = quote.Replace(title), quote.Replace(banner)
+		case "linux", "freebsd", "openbsd", "netbsd":
+			template = []string{"notify-send", "{title}", "{banner}"}
+		default:
+			return nil, fmt.Errorf("no default notification command on %s, set notify_command in the config", runtime.GOOS)
+		}
+	}
+	r := strings.NewReplacer("{title}", title, "{banner}", banner)
+	argv := make([]string, len(template))
+	for i, arg := range template {
+		argv[i] = r.Replace(arg)
+	}
+	return argv, nil
+}
+
+// notifyCmd renders text in the notification font (or leaves it plain if the
+// font isn't installed) and runs the hook.
+func notifyCmd(env cliEnv, title, text string) tea.Cmd {
+	return func() tea.Msg {
+		banner := text
+		fontName := env.cfg.NotifyFont
+		if fontName == "" {
+			fontName = defaultNotifyFont
+		}
+		if font, err := pickFont(env.fonts, fontName, newRand(globalOptions.seed, globalOptions.seeded)); err == nil {
+			if output, err := runFiglet(env.figletCmdPath, font.Path, text, 40); err == nil {
+				banner = strings.TrimRight(output, "\n")
+			}
+		}
+		argv, err := notifyArgv(env.cfg, title, banner)
+		if err != nil {
+			return notifyDoneMsg{err}
+		}
+		if out, err := exec.Command(argv[0], argv[1:]...).CombinedOutput(); err != nil {
+			return notifyDoneMsg{fmt.Errorf("notification command %s failed: %w: %s", argv[0], err, strings.TrimSpace(string(out)))}
+		}
+		return notifyDoneMsg{}
+	}
+}
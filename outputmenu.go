@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Output Menu ---
+// After rendering, the output targets are a menu: pick one with the arrows
+// and Enter, or press its shortcut. New targets only need an entry in
+// outputTargets.
+
+type outputTarget struct {
+	Name     string
+	Desc     string
+	Shortcut key.Binding
+	Choose   func(m model) (model, tea.Cmd)
+}
+
+// For list.Item interface
+func (t outputTarget) Title() string       { return fmt.Sprintf("%s (%s)", t.Name, t.Shortcut.Help().Key) }
+func (t outputTarget) Description() string { return t.Desc }
+func (t outputTarget) FilterValue() string { return t.Name }
+
+func (m model) outputTargets() []outputTarget {
+	return []outputTarget{
+		{Name: "Terminal", Desc: "Scroll through the full render here", Shortcut: m.keys.OutputTerminal, Choose: model.showInTerminal},
+		{Name: "File", Desc: "Save as text, C header, YAML, login banner or shell script", Shortcut: m.keys.OutputFile, Choose: model.promptFilename},
+		{Name: "Compose", Desc: "Add to a composition of several renders, arranged in rows and columns", Shortcut: m.keys.OutputCompose, Choose: model.addToComposition},
+		{Name: "Clipboard", Desc: "Copy as plain text to paste elsewhere, e.g. into a chat; works over SSH and in tmux", Shortcut: m.keys.OutputCopy, Choose: model.copyToClipboard},
+		{Name: "Print", Desc: "Quit and print to stdout, to keep it in the scrollback or pipe it on", Shortcut: m.keys.OutputPrint, Choose: model.printAndQuit},
+	}
+}
+
+// outputShortcut finds the target whose shortcut msg is.
+func (m model) outputShortcut(msg tea.KeyMsg) (outputTarget, bool) {
+	for _, t := range m.outputTargets() {
+		if key.Matches(msg, t.Shortcut) {
+			return t, true
+		}
+	}
+	return outputTarget{}, false
+}
+
+func (m model) newOutputMenu() list.Model {
+	targets := m.outputTargets()
+	items := make([]list.Item, len(targets))
+	for i, t := range targets {
+		items[i] = t
+	}
+	listHeight := m.termHeight - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView()) - 4 // Margins and the prompt
+	l := list.New(items, list.NewDefaultDelegate(), m.termWidth-docStyle.GetHorizontalFrameSize(), listHeight)
+	l.Title = "Output"
+	l.Styles.Title = listTitleStyle
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false) // Letters are shortcuts here
+	l.SetShowHelp(false)         // The footer lists the menu's keys
+	return l
+}
+
+// toOutputChoice shows the output menu, keeping the highlighted target.
+func (m model) toOutputChoice() model {
+	selected := m.outputMenu.Index()
+	m.outputMenu = m.newOutputMenu()
+	m.outputMenu.Select(selected)
+	m.state = stateOutputChoice
+	m.statusMessage = m.outputChoicePrompt()
+	return m
+}
+
+func (m model) showInTerminal() (model, tea.Cmd) {
+	m.figletViewport = viewport.New(m.termWidth-docStyle.GetHorizontalFrameSize(), m.termHeight-lipgloss.Height(m.headerView())-lipgloss.Height(m.footerView())-2)
+	m.figletViewport.Style = figletOutputStyle
+	m.figletViewport.SetContent(m.outputText())
+	m.figletViewport.GotoTop()
+	m.state = stateDisplayFiglet
+	m.statusMessage = ""
+	return m, nil
+}
+
+func (m model) promptFilename() (model, tea.Cmd) {
+	m.textInput.Placeholder = m.filenamePlaceholder()
+	m.textInput.SetValue(m.defaultSavePath()) // Clear for filename, or prefill the preset's target
+	m.state = stateSaveFileNameInput
+	m.statusMessage = ""
+	return m, m.textInput.Focus()
+}
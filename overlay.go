@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// --- Stream Overlay ---
+// `fontlet overlay --out banner.txt --text "Back at {time}"` keeps a file up to
+// date with a render, for OBS to show as a text source ("Read from file") or,
+// with --format html, as a browser source that reloads itself. The text is
+// --text, whose placeholders are filled in anew every --interval, or comes
+// from --input: a file re-read every interval, or with "-" each line of
+// stdin as it arrives. The file is only rewritten when the render changes,
+// atomically, so OBS never reads half a banner. With --format html, --filter
+// metal or rainbow colors the banner and --animate moves the colors along
+// every interval. Ctrl+C stops it, leaving the last banner in place.
+
+type overlayOptions struct {
+	out, text, input, font, format, filter string
+	width                                  int
+	interval                               time.Duration
+	animate                                bool
+}
+
+func runOverlay(args []string, stdout io.Writer) error {
+	fs := newFlagSet("overlay")
+	var o overlayOptions
+	fs.StringVar(&o.out, "out", "", "file to keep the banner in, for OBS to read")
+	fs.StringVar(&o.text, "text", "", "text to render, with placeholders like {time} filled in every interval")
+	fs.StringVar(&o.input, "input", "", "file to take the text from whenever it changes, or - for each line of stdin")
+	fs.StringVar(&o.font, "font", "standard", `font to render in ("random" for a random one)`)
+	fs.IntVar(&o.width, "width", 80, "width of the banner in columns")
+	fs.DurationVar(&o.interval, "interval", time.Second, "how often to update the placeholders, check --input and move --animate's colors")
+	fs.StringVar(&o.format, "format", "text", "text for an OBS text source, html for a browser source")
+	fs.StringVar(&o.filter, "filter", "", "output filter: braille, half-height, or with --format html metal or rainbow")
+	fs.BoolVar(&o.animate, "animate", false, "move the colors of the metal or rainbow filter every interval")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := o.validate(); err != nil {
+		fs.Usage()
+		return err
+	}
+
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	font, err := pickFontFuzzy(env.fonts, o.font, newRand(globalOptions.seed, globalOptions.seeded))
+	if err != nil {
+		return err
+	}
+	if _, err := o.render(env, font, o.text, 0); err != nil { // Mistakes like unknown placeholders show up right away
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer shutdown() // Removes the temp file of a write that was interrupted
+	var lines chan string
+	if o.input == "-" {
+		lines = make(chan string)
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				lines <- strings.TrimSpace(stripANSI(scanner.Text()))
+			}
+			close(lines) // The last line stays up
+		}()
+	}
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	fmt.Fprintf(stdout, "Keeping %s up to date in %s, Ctrl+C to stop\n", o.out, font.Name)
+
+	text, written, warned := o.text, "", ""
+	warn := func(err error) { // Once per problem, it will likely persist a while
+		if err.Error() != warned {
+			fmt.Fprintln(os.Stderr, stderrError(err.Error()))
+			warned = err.Error()
+		}
+	}
+	for frame := 0; ; {
+		if o.input != "" && o.input != "-" {
+			if data, err := os.ReadFile(expandHome(o.input)); err != nil {
+				warn(err) // Keep the last text, the file may be being replaced
+			} else {
+				text = strings.TrimSpace(string(data))
+			}
+		}
+		if content, err := o.render(env, font, text, frame); err != nil {
+			warn(err)
+		} else if content != written {
+			err := writeAtomic(o.out, env.cfg.fileMode(), func(f *os.File) error {
+				_, err := io.WriteString(f, content)
+				return err
+			})
+			if err != nil {
+				warn(fmt.Errorf("failed to write %s: %w", o.out, err))
+			} else {
+				written, warned = content, ""
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if o.animate {
+				frame++
+			}
+		case line, ok := <-lines:
+			if !ok {
+				lines = nil // Blocks forever, leaving the ticker
+			} else if line != "" {
+				text = line
+			}
+		}
+	}
+}
+
+func (o overlayOptions) validate() error {
+	switch {
+	case o.out == "":
+		return fmt.Errorf("overlay needs --out, the file OBS reads")
+	case (o.text == "") == (o.input == ""):
+		return fmt.Errorf("overlay needs either --text or --input")
+	case o.interval <= 0:
+		return fmt.Errorf("--interval must be positive, e.g. 1s or 500ms")
+	case o.format != "text" && o.format != "html":
+		return fmt.Errorf("invalid --format %q, expected text or html", o.format)
+	}
+	_, colored := colorFilterFrames[o.filter]
+	switch {
+	case o.filter != "" && !colored && findOutputFilter(o.filter) == nil:
+		return fmt.Errorf("unknown --filter %q", o.filter)
+	case colored && o.format != "html":
+		return fmt.Errorf("--filter %s colors the banner, which only --format html shows", o.filter)
+	case o.animate && !colored:
+		return fmt.Errorf("--animate needs --filter metal or rainbow")
+	}
+	return nil
+}
+
+// render is the file's content for text at a frame of the animation. Only
+// --text has placeholders: lines from elsewhere are shown as they are.
+func (o overlayOptions) render(env cliEnv, font fontMetadata, text string, frame int) (string, error) {
+	if o.text != "" {
+		var err error
+		if text, err = expandTemplate(text, footerVars(time.Now())); err != nil {
+			return "", err
+		}
+	}
+	var banner string
+	if text != "" { // An empty input file clears the overlay
+		var err error
+		if banner, err = runFiglet(env.figletCmdPath, font.Path, text, o.width); err != nil {
+			return "", err
+		}
+	}
+	if frames, ok := colorFilterFrames[o.filter]; ok {
+		banner = frames(banner, frame)
+	} else if f := findOutputFilter(o.filter); f != nil {
+		banner = f.Apply(banner)
+	}
+	if o.format == "html" {
+		return overlayPage(exportHTML(banner), o.interval), nil
+	}
+	return stripANSI(banner), nil
+}
+
+// overlayPage is a browser source page that reloads itself to pick up the
+// next banner, on a transparent background.
+func overlayPage(pre string, interval time.Duration) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="%d">
+<style>body { margin: 0; background: transparent; color: white; } pre { margin: 0; font-family: monospace; }</style>
+</head>
+<body>%s</body>
+</html>
+`, max(int(interval.Round(time.Second)/time.Second), 1), pre)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Existing Files ---
+// Saving over a file with different content asks first: overwrite, save
+// under the next free numbered name (banner-2.txt), or look at a diff.
+
+var (
+	diffAddedStyle   = renderer.NewStyle().Foreground(lipgloss.Color("76"))
+	diffRemovedStyle = renderer.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+// existingDiffers reports whether path exists with content other than content.
+func existingDiffers(path, content string) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return string(existing) != content, nil
+}
+
+// nextFreeName numbers path like banner-2.txt until the name is unused. The
+// number is always appended to the name as typed: a trailing number may be
+// part of it (motd-2024-05-01.txt), so it's never taken for a counter.
+func nextFreeName(path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, n, ext)
+		if _, err := os.Lstat(candidate); errors.Is(err, fs.ErrNotExist) {
+			return candidate
+		}
+	}
+}
+
+// maxDiffCells bounds the line diff's table, beyond it only sizes are compared.
+const maxDiffCells = 4_000_000
+
+// lineDiff compares two texts line by line (longest common subsequence) and
+// returns the lines prefixed with "  ", "- " (only in old) or "+ " (only in new).
+func lineDiff(old, new string) []string {
+	a, b := strings.Split(old, "\n"), strings.Split(new, "\n")
+	if len(a)*len(b) > maxDiffCells {
+		return []string{fmt.Sprintf("Too large to diff: %d lines on disk, %d lines to save.", len(a), len(b))}
+	}
+
+	// lcs[i][j] is the common subsequence length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	return lines
+}
+
+// newDiffViewport shows what saving the pending file would change.
+func (m model) newDiffViewport() (viewport.Model, error) {
+	existing, err := os.ReadFile(m.pendingSave.path)
+	if err != nil {
+		return viewport.Model{}, err
+	}
+	lines := []string{fmt.Sprintf("Binary files differ: %d bytes on disk, %d bytes to save.", len(existing), len(m.pendingSave.content))}
+	if !exportFormats[m.exportIndex].Binary {
+		lines = lineDiff(stripANSI(string(existing)), stripANSI(m.pendingSave.content))
+	}
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+ "):
+			lines[i] = diffAddedStyle.Render(line)
+		case strings.HasPrefix(line, "- "):
+			lines[i] = diffRemovedStyle.Render(line)
+		}
+	}
+	h, _ := docStyle.GetFrameSize()
+	vp := viewport.New(m.termWidth-h, m.termHeight-lipgloss.Height(m.headerView())-lipgloss.Height(m.footerView())-4) // Margins and title line
+	vp.SetContent(strings.Join(lines, "\n"))
+	return vp, nil
+}
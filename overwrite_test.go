@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNextFreeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		want     string
+	}{
+		{"banner.txt", nil, "banner-2.txt"},
+		{"banner.txt", []string{"banner-2.txt", "banner-3.txt"}, "banner-4.txt"},
+		{"motd-2024-05-01.txt", nil, "motd-2024-05-01-2.txt"},
+		{"banner-2.txt", nil, "banner-2-2.txt"},
+		{"banner", []string{"banner-2"}, "banner-3"},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for _, name := range tt.existing {
+			if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := nextFreeName(filepath.Join(dir, tt.name)); got != filepath.Join(dir, tt.want) {
+			t.Errorf("nextFreeName(%q) with %v = %q, want %q", tt.name, tt.existing, filepath.Base(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,569 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// --- Font Packs ---
+// `fontlet packs install NAME` downloads a collection of fonts listed in the
+// pack manifest (config "pack_manifest", a URL or a local file) into the data
+// dir, where discovery picks them up after the configured font dirs. Every
+// font must match the SHA-256 checksum in the manifest, so a tampered or
+// truncated download is refused before anything is installed. A pack is
+// installed at its newest version unless one is pinned with NAME@VERSION.
+//
+//	{"packs": [{"name": "classic", "description": "The figlet.org collection",
+//	  "versions": [{"version": "1.1", "fonts": [{"name": "slant", "url": "classic/slant.flf", "sha256": "..."}]}]}]}
+//
+// Font URLs are resolved relative to the manifest. Downloads go through the
+// proxy in HTTP_PROXY/HTTPS_PROXY (NO_PROXY exempts hosts), and with
+// "pack_mirror" set, everything under the manifest's directory is fetched
+// from the mirror instead. When the network is unreachable, fontlet says so
+// and keeps working with the fonts already installed.
+
+type packManifest struct {
+	Packs []manifestPack `json:"packs"`
+}
+
+type manifestPack struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Versions    []packVersion `json:"versions"`
+}
+
+type packVersion struct {
+	Version string     `json:"version"`
+	Fonts   []packFont `json:"fonts"`
+}
+
+type packFont struct {
+	Name   string `json:"name"`
+	URL    string `json:"url,omitempty"` // Manifest only
+	SHA256 string `json:"sha256"`
+}
+
+// installedPack is recorded as pack.json in the pack's directory.
+type installedPack struct {
+	Name      string     `json:"name"`
+	Version   string     `json:"version"`
+	Pinned    bool       `json:"pinned,omitempty"` // Installed as NAME@VERSION
+	Installed time.Time  `json:"installed"`
+	Fonts     []packFont `json:"fonts"`
+}
+
+// maxFontDownload bounds a single font; the largest classic fonts are ~100 KiB.
+const maxFontDownload = 4 << 20
+
+// packsDir is where packs are installed, one directory each.
+func packsDir() (string, error) {
+	if noState {
+		return "", errNoState
+	}
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "fontlet", "packs"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "fontlet", "packs"), nil
+}
+
+// packHTTPClient uses http.DefaultTransport, which honors the proxy variables.
+var packHTTPClient = &http.Client{Timeout: 60 * time.Second}
+
+// offlineError is a download that failed because the network or the host is unreachable.
+type offlineError struct {
+	location string
+	err      error
+}
+
+func (e offlineError) Error() string {
+	return fmt.Sprintf("can't reach %s, working offline (installed fonts are still available): %v", e.location, e.err)
+}
+
+func (e offlineError) Unwrap() error { return e.err }
+
+// isUnreachable tells network failures (DNS, refused or unroutable
+// connections, timeouts) apart from answers like 404.
+func isUnreachable(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr) || os.IsTimeout(err)
+}
+
+// packSource locates the manifest and its fonts, through the mirror if one is configured.
+type packSource struct {
+	manifest string
+	mirror   string
+}
+
+func newPackSource(cfg config) packSource {
+	return packSource{manifest: expandHome(cfg.PackManifest), mirror: strings.TrimRight(expandHome(cfg.PackMirror), "/")}
+}
+
+// locate maps a location under the manifest's directory to the mirror.
+func (s packSource) locate(location string) string {
+	if s.mirror == "" {
+		return location
+	}
+	base := s.manifest[:strings.LastIndex(s.manifest, "/")+1]
+	if rest, ok := strings.CutPrefix(location, base); ok {
+		return s.mirror + "/" + rest
+	}
+	return location
+}
+
+// fetch reads a URL, or a local file for locations without a scheme.
+func fetch(location string, limit int64) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		data, err := os.ReadFile(location)
+		if err == nil && int64(len(data)) > limit {
+			return nil, fmt.Errorf("%s is over %d bytes", location, limit)
+		}
+		return data, err
+	}
+	resp, err := packHTTPClient.Get(location)
+	if err != nil {
+		if isUnreachable(err) {
+			return nil, offlineError{location: u.Host, err: err}
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", location, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is over %d bytes", location, limit)
+	}
+	return data, nil
+}
+
+// resolveLocation resolves ref against the manifest's location, URL or path.
+func resolveLocation(base, ref string) string {
+	if u, err := url.Parse(ref); err == nil && u.Scheme != "" {
+		return ref
+	}
+	if b, err := url.Parse(base); err == nil && (b.Scheme == "http" || b.Scheme == "https") {
+		if r, err := url.Parse(ref); err == nil {
+			return b.ResolveReference(r).String()
+		}
+	}
+	if filepath.IsAbs(ref) {
+		return ref
+	}
+	return filepath.Join(filepath.Dir(base), ref)
+}
+
+func loadManifest(cfg config) (packManifest, error) {
+	if cfg.PackManifest == "" {
+		return packManifest{}, errors.New(`no pack manifest configured, set "pack_manifest" in the config`)
+	}
+	src := newPackSource(cfg)
+	data, err := fetch(src.locate(src.manifest), maxFontDownload)
+	var offline offlineError
+	if errors.As(err, &offline) {
+		return packManifest{}, offline
+	}
+	if err != nil {
+		return packManifest{}, fmt.Errorf("failed to fetch the pack manifest: %w", err)
+	}
+	var m packManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return packManifest{}, fmt.Errorf("invalid pack manifest %s: %w", cfg.PackManifest, err)
+	}
+	return m, nil
+}
+
+// latest is the newest version by dotted numeric comparison.
+func (p manifestPack) latest() (packVersion, bool) {
+	if len(p.Versions) == 0 {
+		return packVersion{}, false
+	}
+	newest := p.Versions[0]
+	for _, v := range p.Versions[1:] {
+		if compareVersions(v.Version, newest.Version) > 0 {
+			newest = v
+		}
+	}
+	return newest, true
+}
+
+// compareVersions compares dotted versions like "1.10" > "1.9"; parts that
+// aren't numbers compare as strings.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case (xerr != nil || yerr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+func loadInstalledPacks() ([]installedPack, error) {
+	dir, err := packsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var packs []installedPack
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name(), "pack.json"))
+		if err != nil {
+			continue // Not a pack, e.g. an interrupted install
+		}
+		var p installedPack
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("invalid pack record %s: %w", filepath.Join(dir, e.Name(), "pack.json"), err)
+		}
+		packs = append(packs, p)
+	}
+	return packs, nil
+}
+
+// validPackName keeps manifest names from escaping the packs dir.
+func validPackName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`) && !strings.HasPrefix(name, ".")
+}
+
+// downloadFont fetches a font and checks it against the manifest: checksum
+// first, then that it's a FIGfont at all.
+func downloadFont(src packSource, f packFont) ([]byte, error) {
+	if !validPackName(f.Name) {
+		return nil, fmt.Errorf("invalid font name %q in the manifest", f.Name)
+	}
+	if f.SHA256 == "" {
+		return nil, fmt.Errorf("%s has no checksum in the manifest, refusing to install it", f.Name)
+	}
+	data, err := fetch(src.locate(resolveLocation(src.manifest, f.URL)), maxFontDownload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", f.Name, err)
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, f.SHA256) {
+		return nil, fmt.Errorf("%s failed checksum verification (expected %s, got %s), refusing to install it", f.Name, f.SHA256, got)
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	if _, err := parseFLFHeader(line); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return data, nil
+}
+
+// installPack downloads and verifies every font before touching the
+// installed pack, then swaps the pack directory in.
+func installPack(cfg config, pack manifestPack, version packVersion, pinned bool) (installedPack, error) {
+	dir, err := packsDir()
+	if err != nil {
+		return installedPack{}, err
+	}
+	if !validPackName(pack.Name) {
+		return installedPack{}, fmt.Errorf("invalid pack name %q in the manifest", pack.Name)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return installedPack{}, err
+	}
+	tmp, err := os.MkdirTemp(dir, ".install-"+pack.Name+"-")
+	if err != nil {
+		return installedPack{}, err
+	}
+	defer os.RemoveAll(tmp)                     // Gone after the rename on success
+	if err := os.Chmod(tmp, 0755); err != nil { // MkdirTemp makes it private
+		return installedPack{}, err
+	}
+
+	record := installedPack{Name: pack.Name, Version: version.Version, Pinned: pinned, Installed: time.Now()}
+	for _, f := range version.Fonts {
+		data, err := downloadFont(newPackSource(cfg), f)
+		if err != nil {
+			return installedPack{}, err
+		}
+		if err := os.WriteFile(filepath.Join(tmp, f.Name+".flf"), data, 0644); err != nil {
+			return installedPack{}, err
+		}
+		record.Fonts = append(record.Fonts, packFont{Name: f.Name, SHA256: strings.ToLower(f.SHA256)})
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return installedPack{}, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "pack.json"), append(data, '\n'), 0644); err != nil {
+		return installedPack{}, err
+	}
+
+	target := filepath.Join(dir, pack.Name)
+	if err := os.RemoveAll(target); err != nil {
+		return installedPack{}, err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return installedPack{}, err
+	}
+	return record, nil
+}
+
+func runPacks(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		newFlagSet("packs").Usage()
+		return fmt.Errorf("missing packs action: list, install or upgrade")
+	}
+	switch args[0] {
+	case "list":
+		return runPacksList(stdout)
+	case "install":
+		return runPacksInstall(args[1:], stdout)
+	case "upgrade":
+		return runPacksUpgrade(args[1:], stdout)
+	}
+	return fmt.Errorf("unknown packs action %q, expected list, install or upgrade", args[0])
+}
+
+// runPacksList shows the manifest's packs and which are installed.
+func runPacksList(stdout io.Writer) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	installed, err := loadInstalledPacks()
+	if err != nil {
+		return err
+	}
+	versions := make(map[string]string)
+	for _, p := range installed {
+		versions[p.Name] = p.Version
+		if p.Pinned {
+			versions[p.Name] += " (pinned)"
+		}
+	}
+	manifest, err := loadManifest(cfg)
+	var offline offlineError
+	if errors.As(err, &offline) {
+		fmt.Fprintln(os.Stderr, stderrError(offline.Error()))
+		return printInstalledPacks(stdout, installed)
+	}
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACK\tLATEST\tINSTALLED\tDESCRIPTION")
+	for _, p := range manifest.Packs {
+		latest, _ := p.latest()
+		have := versions[p.Name]
+		if have == "" {
+			have = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Name, latest.Version, have, p.Description)
+	}
+	return tw.Flush()
+}
+
+// printInstalledPacks lists what's installed, when the manifest can't be reached.
+func printInstalledPacks(stdout io.Writer, installed []installedPack) error {
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACK\tINSTALLED\tFONTS")
+	for _, p := range installed {
+		version := p.Version
+		if p.Pinned {
+			version += " (pinned)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", p.Name, version, len(p.Fonts))
+	}
+	return tw.Flush()
+}
+
+func runPacksInstall(args []string, stdout io.Writer) error {
+	fs := newFlagSet("packs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("packs install needs a pack name")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	manifest, err := loadManifest(cfg)
+	if err != nil {
+		return err
+	}
+	for _, arg := range fs.Args() {
+		name, pin, pinned := strings.Cut(arg, "@")
+		var pack *manifestPack
+		for i := range manifest.Packs {
+			if manifest.Packs[i].Name == name {
+				pack = &manifest.Packs[i]
+			}
+		}
+		if pack == nil {
+			return fmt.Errorf("no pack named %q in the manifest", name)
+		}
+		version, ok := pack.latest()
+		if pinned {
+			ok = false
+			for _, v := range pack.Versions {
+				if v.Version == pin {
+					version, ok = v, true
+				}
+			}
+		}
+		if !ok {
+			return fmt.Errorf("pack %s has no version %q", name, pin)
+		}
+		record, err := installPack(cfg, *pack, version, pinned)
+		if err != nil {
+			return fmt.Errorf("failed to install %s, nothing was changed: %w", arg, err)
+		}
+		fmt.Fprintf(stdout, "Installed %s %s (%d fonts)\n", record.Name, record.Version, len(record.Fonts))
+	}
+	return nil
+}
+
+// packDiff compares the fonts of an installed pack with a manifest version.
+func packDiff(installed []packFont, next packVersion) (added, removed, changed []string) {
+	have := make(map[string]string)
+	for _, f := range installed {
+		have[f.Name] = f.SHA256
+	}
+	for _, f := range next.Fonts {
+		sum, ok := have[f.Name]
+		switch {
+		case !ok:
+			added = append(added, f.Name)
+		case !strings.EqualFold(sum, f.SHA256):
+			changed = append(changed, f.Name)
+		}
+		delete(have, f.Name)
+	}
+	for name := range have {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	return added, removed, changed
+}
+
+// runPacksUpgrade brings installed packs to the manifest's newest version,
+// and reinstalls fonts whose checksum changed within the same version.
+// Pinned packs are left alone.
+func runPacksUpgrade(args []string, stdout io.Writer) error {
+	fs := newFlagSet("packs")
+	dryRun := fs.Bool("dry-run", false, "only report what would change")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	installed, err := loadInstalledPacks()
+	if err != nil {
+		return err
+	}
+	only := make(map[string]bool) // Packs named on the command line, all when empty
+	for _, name := range fs.Args() {
+		found := false
+		for _, p := range installed {
+			found = found || p.Name == name
+		}
+		if !found {
+			return fmt.Errorf("pack %s isn't installed", name)
+		}
+		only[name] = true
+	}
+	manifest, err := loadManifest(cfg)
+	if err != nil {
+		return err
+	}
+	available := make(map[string]manifestPack)
+	for _, p := range manifest.Packs {
+		available[p.Name] = p
+	}
+
+	upToDate := 0
+	for _, have := range installed {
+		if len(only) > 0 && !only[have.Name] {
+			continue
+		}
+		if have.Pinned {
+			fmt.Fprintf(stdout, "%s: pinned at %s, skipped (packs install %s unpins it)\n", have.Name, have.Version, have.Name)
+			continue
+		}
+		pack, ok := available[have.Name]
+		latest, hasVersions := pack.latest()
+		if !ok || !hasVersions {
+			fmt.Fprintf(stdout, "%s: no longer in the manifest, kept at %s\n", have.Name, have.Version)
+			continue
+		}
+		added, removed, changed := packDiff(have.Fonts, latest)
+		if compareVersions(latest.Version, have.Version) <= 0 && len(added)+len(removed)+len(changed) == 0 {
+			upToDate++
+			continue
+		}
+		if latest.Version == have.Version {
+			fmt.Fprintf(stdout, "%s: %s, fonts changed in the manifest\n", have.Name, have.Version)
+		} else {
+			fmt.Fprintf(stdout, "%s: %s -> %s\n", have.Name, have.Version, latest.Version)
+		}
+		for _, change := range []struct {
+			sign  string
+			fonts []string
+		}{{"+", added}, {"-", removed}, {"~", changed}} {
+			for _, name := range change.fonts {
+				fmt.Fprintf(stdout, "  %s %s\n", change.sign, name)
+			}
+		}
+		if *dryRun {
+			continue
+		}
+		if _, err := installPack(cfg, pack, latest, false); err != nil {
+			return fmt.Errorf("failed to upgrade %s, it was left at %s: %w", have.Name, have.Version, err)
+		}
+	}
+	if upToDate > 0 {
+		fmt.Fprintf(stdout, "%d packs already up to date\n", upToDate)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownloadFontChecksum(t *testing.T) {
+	font := "flf2a$ 1 1 3 0 0\n"
+	notFont := "<html>not found</html>\n"
+	dir := t.TempDir()
+	for name, data := range map[string]string{"mini.flf": font, "page.flf": notFont} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer srv.Close()
+
+	local := packSource{manifest: filepath.Join(dir, "manifest.json")}
+	remote := packSource{manifest: srv.URL + "/manifest.json"}
+	tests := []struct {
+		name    string
+		src     packSource
+		font    packFont
+		wantErr string // Substring of the error, "" for none
+	}{
+		{"local", local, packFont{Name: "mini", URL: "mini.flf", SHA256: sha256Hex(font)}, ""},
+		{"http", remote, packFont{Name: "mini", URL: "mini.flf", SHA256: sha256Hex(font)}, ""},
+		{"uppercase checksum", remote, packFont{Name: "mini", URL: "mini.flf", SHA256: strings.ToUpper(sha256Hex(font))}, ""},
+		{"mismatch", remote, packFont{Name: "mini", URL: "mini.flf", SHA256: sha256Hex(font + " ")}, "failed checksum verification"},
+		{"truncated checksum", remote, packFont{Name: "mini", URL: "mini.flf", SHA256: sha256Hex(font)[:32]}, "failed checksum verification"},
+		{"no checksum", remote, packFont{Name: "mini", URL: "mini.flf"}, "no checksum"},
+		{"checksum of another file", remote, packFont{Name: "mini", URL: "page.flf", SHA256: sha256Hex(font)}, "failed checksum verification"},
+		{"verified but not a font", remote, packFont{Name: "page", URL: "page.flf", SHA256: sha256Hex(notFont)}, "header"},
+		{"missing", remote, packFont{Name: "gone", URL: "gone.flf", SHA256: sha256Hex(font)}, "404"},
+		{"name escaping the pack", remote, packFont{Name: "../mini", URL: "mini.flf", SHA256: sha256Hex(font)}, "invalid font name"},
+		{"hidden name", remote, packFont{Name: ".mini", URL: "mini.flf", SHA256: sha256Hex(font)}, "invalid font name"},
+	}
+	for _, tt := range tests {
+		data, err := downloadFont(tt.src, tt.font)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: downloadFont error %v", tt.name, err)
+		case tt.wantErr == "" && string(data) != font:
+			t.Errorf("%s: downloadFont = %q, want %q", tt.name, data, font)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: downloadFont error %v, want %q", tt.name, err, tt.wantErr)
+		case tt.wantErr != "" && data != nil:
+			t.Errorf("%s: downloadFont returned data with an error", tt.name)
+		}
+	}
+}
+
+func TestPackSourceLocate(t *testing.T) {
+	src := packSource{manifest: "https://example.com/packs/manifest.json", mirror: "https://mirror.example.org/fontlet"}
+	tests := []struct {
+		location, want string
+	}{
+		{"https://example.com/packs/classic/slant.flf", "https://mirror.example.org/fontlet/classic/slant.flf"},
+		{"https://elsewhere.example.net/slant.flf", "https://elsewhere.example.net/slant.flf"},
+	}
+	for _, tt := range tests {
+		if got := src.locate(tt.location); got != tt.want {
+			t.Errorf("locate(%q) = %q, want %q", tt.location, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// --- Piped Text ---
+// `echo "release 2.1" | fontlet` and `fontlet -i notes.txt` take the text from
+// stdin or a file instead of the text input: the TUI starts with it as with
+// --text, and --font or --output render it without the TUI. Stdin is only read
+// when it's a pipe or a redirected file, never a terminal; keys still come from
+// the terminal. The TUI's single-line input joins multiple lines with
+// paste_join, while scripted renders keep the line breaks.
+
+// readInputText reads the text from path ("-" for stdin), or from stdin when
+// it isn't a terminal and path is empty. Surrounding whitespace is trimmed.
+func readInputText(path string, stdin *os.File) (string, error) {
+	var data []byte
+	var err error
+	switch {
+	case path == "-" || path == "" && stdinRedirected(stdin):
+		data, err = io.ReadAll(stdin)
+	case path != "":
+		data, err = os.ReadFile(expandHome(path))
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the text: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// stdinRedirected reports whether f is a pipe or a file rather than a
+// terminal or nothing at all.
+func stdinRedirected(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&(os.ModeNamedPipe|os.ModeCharDevice) == os.ModeNamedPipe || info.Mode().IsRegular()
+}
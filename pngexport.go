@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- PNG Export ---
+// The PNG format draws the banner as an image, for slides and stream graphics
+// that can't show monospace text, and --image shows the same image in the
+// terminal (see inlineimage.go). Each character cell becomes a block of
+// pixels on a transparent background: the characters figlet fonts draw
+// lines with (_ | / \ -) become strokes, block and braille characters the
+// parts of the cell they cover, and anything else fills its cell. Cells take
+// their ANSI color where colors are kept, the theme's banner color elsewhere.
+
+// Pixels per character cell, the shape of a terminal's cells and large
+// enough to stay sharp when a terminal scales the image to its font.
+const pngCellWidth, pngCellHeight = 16, 32
+
+// exportPNG encodes the banner as a PNG, binary content (see exportFormat).
+func exportPNG(banner string, cfg config) (string, error) {
+	data, err := bannerPNG(banner, bannerRGB(cfg.Theme))
+	return string(data), err
+}
+
+// bannerRGB is the theme's banner color, with applyTheme's default.
+func bannerRGB(t themeConfig) [3]int {
+	if n, err := strconv.Atoi(t.Output); err == nil && n >= 0 && n < 256 {
+		return ansi256RGB(n)
+	}
+	var rgb [3]int
+	if _, err := fmt.Sscanf(t.Output, "#%02x%02x%02x", &rgb[0], &rgb[1], &rgb[2]); err == nil && len(t.Output) == 7 {
+		return rgb
+	}
+	return ansi256RGB(69)
+}
+
+// bannerPNG draws the banner, in fg where it has no color of its own.
+func bannerPNG(banner string, fg [3]int) ([]byte, error) {
+	lines := bannerLines(banner)
+	cols := 1 // An empty image can't be encoded
+	for _, line := range lines {
+		cols = max(cols, lipgloss.Width(line))
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, cols*pngCellWidth, len(lines)*pngCellHeight))
+	current := fg // Colors carry over line ends, as in a terminal
+	for y, line := range lines {
+		x := 0
+		draw := func(s string) {
+			for _, r := range s {
+				w := lipgloss.Width(string(r))
+				if r != ' ' && w > 0 {
+					drawCell(img, x, y, w, r, current)
+				}
+				x += w
+			}
+		}
+		last := 0
+		for _, loc := range ansiPattern.FindAllStringIndex(line, -1) {
+			draw(line[last:loc[0]])
+			last = loc[1]
+			seq := line[loc[0]:loc[1]]
+			if !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "m") {
+				continue // Not a color (SGR) sequence
+			}
+			sgrForeground(strings.Split(seq[2:len(seq)-1], ";"), func(rgb *[3]int, _ bool) {
+				current = fg
+				if rgb != nil {
+					current = *rgb
+				}
+			})
+		}
+		draw(line[last:])
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawCell paints the part of the cells (two for wide characters) at column
+// x of line y that r covers.
+func drawCell(img *image.NRGBA, x, y, cells int, r rune, rgb [3]int) {
+	c := color.NRGBA{uint8(rgb[0]), uint8(rgb[1]), uint8(rgb[2]), 255}
+	covers := cellShape(r)
+	w := cells * pngCellWidth
+	for py := 0; py < pngCellHeight; py++ {
+		for px := 0; px < w; px++ {
+			if covers(float64(px)+0.5, float64(py)+0.5, float64(w)) {
+				img.SetNRGBA(x*pngCellWidth+px, y*pngCellHeight+py, c)
+			}
+		}
+	}
+}
+
+// cellShape reports which pixels of a w wide cell r covers, by their centers.
+// Strokes are about four pixels thick whichever way they run.
+func cellShape(r rune) func(px, py, w float64) bool {
+	const h, stroke = float64(pngCellHeight), 2.0 // Half a stroke
+	dot := func(px, py, w, cy float64) bool { return math.Abs(px-w/2) < 3 && math.Abs(py-cy) < 3 }
+	switch {
+	case r == '_':
+		return func(px, py, w float64) bool { return py > h-2*stroke }
+	case r == '-' || r == '~':
+		return func(px, py, w float64) bool { return math.Abs(py-h/2) < stroke }
+	case r == '=':
+		return func(px, py, w float64) bool { return math.Abs(py-h*0.35) < stroke || math.Abs(py-h*0.65) < stroke }
+	case r == '|' || r == '(' || r == ')' || r == '[' || r == ']':
+		return func(px, py, w float64) bool { return math.Abs(px-w/2) < stroke }
+	case r == '/':
+		return func(px, py, w float64) bool { return math.Abs(px-w*(1-py/h)) < stroke*1.1 }
+	case r == '\\':
+		return func(px, py, w float64) bool { return math.Abs(px-w*py/h) < stroke*1.1 }
+	case r == '<':
+		return func(px, py, w float64) bool { return math.Abs(px-w*math.Abs(py/h-0.5)*2) < stroke*1.1 }
+	case r == '>':
+		return func(px, py, w float64) bool { return math.Abs(px-w*(1-math.Abs(py/h-0.5)*2)) < stroke*1.1 }
+	case r == '.' || r == ',':
+		return func(px, py, w float64) bool { return dot(px, py, w, h-4) }
+	case r == '\'' || r == '`':
+		return func(px, py, w float64) bool { return dot(px, py, w, 4) }
+	case r == ':':
+		return func(px, py, w float64) bool { return dot(px, py, w, h*0.3) || dot(px, py, w, h*0.7) }
+	case r == '▀':
+		return func(px, py, w float64) bool { return py < h/2 }
+	case r == '▄':
+		return func(px, py, w float64) bool { return py >= h/2 }
+	case r == '▌':
+		return func(px, py, w float64) bool { return px < w/2 }
+	case r == '▐':
+		return func(px, py, w float64) bool { return px >= w/2 }
+	case r >= 0x2800 && r <= 0x28ff: // Braille: a 2 by 4 grid of dots
+		bits := [4][2]int{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+		return func(px, py, w float64) bool { return int(r-0x2800)&bits[int(py/h*4)][int(px/w*2)] != 0 }
+	}
+	return func(px, py, w float64) bool { return true }
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Preview Cache ---
+// Previews the TUI renders are kept in the user cache dir, so entering a text
+// seen before, in this session or an earlier one, reads them instead of
+// running figlet per font. `fontlet cache warm` renders a sample text ahead of
+// time. Entries are keyed by font (path and mtime), text, width and figlet
+// binary, so edits and resizes just miss. Ctrl+P in the font list or
+// `fontlet cache clear` purges it. Entries are written atomically, so a
+// concurrent reader never takes half an entry for a hit, and once they take
+// more than the budget (preview_cache_mb, or serve's --cache-dir-mb) the least
+// recently used are evicted, by mtime, which hits refresh.
+
+type previewCache struct {
+	dir    string      // Empty when there's no cache dir; every lookup misses
+	budget int64       // Bytes the entries may take, 0 for no limit
+	usage  *cacheUsage // Shared by every cache of dir in this process
+}
+
+// cacheUsage is the bytes in a cache dir, counted from disk at the first put
+// and kept up to date by the puts after it. Entries other processes write
+// only show up at the next eviction's count, so the budget holds roughly.
+type cacheUsage struct {
+	mu      sync.Mutex
+	counted bool
+	bytes   int64
+}
+
+// cacheUsages maps cache dirs to their *cacheUsage.
+var cacheUsages sync.Map
+
+func newPreviewCache(dir string, budget int64) previewCache {
+	if dir == "" {
+		return previewCache{}
+	}
+	usage, _ := cacheUsages.LoadOrStore(dir, &cacheUsage{})
+	return previewCache{dir: dir, budget: budget, usage: usage.(*cacheUsage)}
+}
+
+// cacheCounters are the lookups made by the TUI since the last clear.
+type cacheCounters struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+const defaultPreviewCache = 100 // MiB
+
+// openPreviewCache opens the user's preview cache with cfg's budget.
+func openPreviewCache(cfg config) previewCache {
+	if noState {
+		return previewCache{}
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return previewCache{}
+	}
+	return newPreviewCache(filepath.Join(dir, "fontlet", "previews"), cfg.previewCacheBudget())
+}
+
+// previewCacheBudget is the preview cache's budget in bytes.
+func (c config) previewCacheBudget() int64 {
+	if c.PreviewCache == 0 {
+		return defaultPreviewCache << 20
+	}
+	return int64(c.PreviewCache) << 20
+}
+
+// previewWidth is the figlet width of in-list previews for a terminal width,
+// a bit less than the terminal to leave room for list padding.
+func previewWidth(termWidth int) int {
+	return max(termWidth-20, 20) // Minimum sensible width
+}
+
+// previewKey identifies a render. The control file is part of it: the same
+// font renders differently once its control file turns up or moves.
+func previewKey(font fontMetadata, text string, width int, figletCmdPath string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s\x00%d\x00%s\x00%s", font.Path, font.ModTime.UnixNano(), text, width, figletCmdPath, font.ControlPath)))
+	return hex.EncodeToString(sum[:])
+}
+
+// entryPath spreads entries over subdirectories so no directory gets huge.
+func (c previewCache) entryPath(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+func (c previewCache) get(key string) (string, bool) {
+	if c.dir == "" {
+		return "", false
+	}
+	path := c.entryPath(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	now := time.Now()
+	os.Chtimes(path, now, now) // Recently used, best effort
+	return string(data), true
+}
+
+func (c previewCache) put(key, output string) error {
+	if c.dir == "" {
+		if noState {
+			return errNoState
+		}
+		return fmt.Errorf("no user cache directory")
+	}
+	path := c.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	err := writeAtomic(path, 0644, func(f *os.File) error {
+		_, err := f.WriteString(output)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return c.charge(int64(len(output)))
+}
+
+// charge adds a new entry's size to the usage and, over the budget, evicts
+// entries down to three quarters of it, so the next puts don't evict again.
+func (c previewCache) charge(size int64) error {
+	if c.budget <= 0 {
+		return nil
+	}
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	if c.usage.counted {
+		c.usage.bytes += size
+	} else {
+		_, bytes, err := c.stats() // Includes the new entry
+		if err != nil {
+			return err
+		}
+		c.usage.bytes, c.usage.counted = bytes, true
+	}
+	if c.usage.bytes <= c.budget {
+		return nil
+	}
+	left, err := c.evict(c.budget * 3 / 4)
+	c.usage.bytes = left
+	return err
+}
+
+// evict removes the least recently used entries until the rest take at most
+// target bytes, returning what they take.
+func (c previewCache) evict(target int64) (int64, error) {
+	type entry struct {
+		path string
+		info fs.FileInfo
+	}
+	var entries []entry
+	var total int64
+	err := c.walk(func(path string, info fs.FileInfo) {
+		entries = append(entries, entry{path, info})
+		total += info.Size()
+	})
+	if err != nil {
+		return total, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].info.ModTime().Before(entries[j].info.ModTime()) })
+	for _, e := range entries {
+		if total <= target {
+			break
+		}
+		if err := os.Remove(e.path); err == nil || errors.Is(err, fs.ErrNotExist) {
+			total -= e.info.Size()
+		}
+	}
+	return total, nil
+}
+
+func (c previewCache) countersPath() string {
+	return filepath.Join(c.dir, "counters.json")
+}
+
+func (c previewCache) counters() cacheCounters {
+	var counters cacheCounters
+	if data, err := os.ReadFile(c.countersPath()); err == nil {
+		json.Unmarshal(data, &counters) // A damaged file just restarts the counts
+	}
+	return counters
+}
+
+type previewCachePurgedMsg struct {
+	entries int
+	size    int64
+	err     error
+}
+
+// purgePreviewCacheCmd clears the preview cache, reporting what it held.
+// Previews already in the list stay, they're kept in memory.
+func purgePreviewCacheCmd(cfg config) tea.Cmd {
+	return func() tea.Msg {
+		cache := openPreviewCache(cfg)
+		entries, size, err := cache.stats()
+		if err == nil {
+			err = cache.clear()
+		}
+		return previewCachePurgedMsg{entries, size, err}
+	}
+}
+
+// recordLookups adds to the hit and miss counters. They're only statistics,
+// so callers may ignore the error.
+func (c previewCache) recordLookups(hits, misses int) error {
+	if c.dir == "" {
+		return nil
+	}
+	if _, err := os.Stat(c.dir); err != nil { // Nothing cached yet, don't create the cache just to count misses
+		return nil
+	}
+	counters := c.counters()
+	counters.Hits += int64(hits)
+	counters.Misses += int64(misses)
+	data, err := json.Marshal(counters)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(c.countersPath(), 0644, func(f *os.File) error {
+		_, err := f.Write(data)
+		return err
+	})
+}
+
+// stats counts the cached previews and their total size.
+func (c previewCache) stats() (entries int, size int64, err error) {
+	err = c.walk(func(_ string, info fs.FileInfo) {
+		entries++
+		size += info.Size()
+	})
+	return entries, size, err
+}
+
+// walk calls fn with every entry, skipping the counters and the temp files
+// of puts in progress. A cache that doesn't exist yet has no entries.
+func (c previewCache) walk(fn func(path string, info fs.FileInfo)) error {
+	if c.dir == "" {
+		return nil
+	}
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path != c.dir {
+				return nil // Evicted or cleared meanwhile
+			}
+			return err
+		}
+		if d.IsDir() || path == c.countersPath() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		fn(path, info)
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (c previewCache) clear() error {
+	if c.dir == "" {
+		return nil
+	}
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	c.usage.counted = false
+	return os.RemoveAll(c.dir)
+}
@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestPreviewKeyControlFile(t *testing.T) {
+	font := fontMetadata{Name: "mini", Path: "/fonts/mini.flf"}
+	withControl := font
+	withControl.ControlPath = "/fonts/utf8.flc"
+	moved := font
+	moved.ControlPath = "/other/utf8.flc"
+
+	keys := map[string]string{}
+	for name, f := range map[string]fontMetadata{"none": font, "control": withControl, "moved": moved} {
+		key := previewKey(f, "Hi", 80, builtinFigletCmd)
+		if other, ok := keys[key]; ok {
+			t.Errorf("previewKey for %s and %s are the same", name, other)
+		}
+		keys[key] = name
+		if renderKey(f, "Hi", 80, "none", builtinFigletCmd) == key {
+			t.Errorf("renderKey for %s equals its previewKey", name)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Preview Memory ---
+// Previews live in a store shared by the model and the list delegate rather
+// than in the fonts, capped at preview_memory_mb. Past the budget the least
+// recently drawn previews are evicted; drawing marks a preview used, so the
+// ones on screen stay. Pages showing evicted previews get them back on demand,
+// from the preview cache when warmed, else by rendering them again.
+
+const defaultPreviewMemory = 64 // MiB
+
+type previewStore struct {
+	mu      sync.Mutex
+	budget  int                      // Bytes
+	size    int                      // Bytes held, keys included
+	order   *list.List               // Front is most recently used; values are *previewEntry
+	entries map[string]*list.Element // By font path
+	loading map[string]bool          // Evicted previews being loaded again
+}
+
+type previewEntry struct {
+	path, preview string
+}
+
+func newPreviewStore(budget int) *previewStore {
+	return &previewStore{budget: budget, order: list.New(), entries: map[string]*list.Element{}, loading: map[string]bool{}}
+}
+
+// previewMemory is the preview store's budget in bytes.
+func (c config) previewMemory() int {
+	if c.PreviewMemory == 0 {
+		return defaultPreviewMemory << 20
+	}
+	return c.PreviewMemory << 20
+}
+
+func (s *previewStore) get(path string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[path]
+	if !ok {
+		return "", false
+	}
+	s.order.MoveToFront(el)
+	return el.Value.(*previewEntry).preview, true
+}
+
+// put stores the preview of the font at path, replacing any previous one.
+func (s *previewStore) put(path, preview string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(path)
+	s.entries[path] = s.order.PushFront(&previewEntry{path, preview})
+	s.size += len(path) + len(preview)
+	s.evict()
+}
+
+// forget drops the preview of the font at path, e.g. after it was edited.
+func (s *previewStore) forget(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(path)
+}
+
+// setBudget changes the budget, e.g. after a config reload.
+func (s *previewStore) setBudget(budget int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.budget = budget
+	s.evict()
+}
+
+// clear drops every preview, as after a new font scan.
+func (s *previewStore) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.order.Init()
+	s.entries = map[string]*list.Element{}
+	s.size = 0
+}
+
+// startLoading reports whether the preview at path is missing and not
+// already being loaded, and if so marks it as being loaded.
+func (s *previewStore) startLoading(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[path]; ok || s.loading[path] {
+		return false
+	}
+	s.loading[path] = true
+	return true
+}
+
+func (s *previewStore) doneLoading(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.loading, path)
+}
+
+// remove and evict expect s.mu to be held.
+func (s *previewStore) remove(path string) {
+	if el, ok := s.entries[path]; ok {
+		s.order.Remove(el)
+		delete(s.entries, path)
+		s.size -= len(path) + len(el.Value.(*previewEntry).preview)
+	}
+}
+
+// The newest preview always stays, however big.
+func (s *previewStore) evict() {
+	for s.size > s.budget && s.order.Len() > 1 {
+		s.remove(s.order.Back().Value.(*previewEntry).path)
+	}
+}
+
+// previewsReloadedMsg carries previews loaded again after being evicted.
+type previewsReloadedMsg struct {
+	target   previewTarget
+	previews map[string]string // By font path
+}
+
+// reloadEvictedPreviews loads the previews missing on the list's current page.
+// While previews stream in, they're all on their way already.
+func (m model) reloadEvictedPreviews() tea.Cmd {
+	if m.state != stateSelectFontWithPreview || m.highlightMode() || m.previewProgress != nil || m.previewed.text == "" {
+		return nil
+	}
+	items := m.fontList.VisibleItems()
+	start, end := m.fontList.Paginator.GetSliceBounds(len(items))
+	var fonts []fontMetadata
+	for _, item := range items[start:end] {
+		if f, ok := item.(fontMetadata); ok && m.previews.startLoading(f.Path) {
+			fonts = append(fonts, f)
+		}
+	}
+	if len(fonts) == 0 {
+		return nil
+	}
+	target := m.previewed
+	return func() tea.Msg {
+		cache := openPreviewCache(m.cfg)
+		previews := make([]string, len(fonts))
+		var hits atomic.Int64
+		parallelFor(len(fonts), func(i int) {
+			var hit bool
+			previews[i], hit = m.loadPreview(cache, fonts[i], target)
+			if hit {
+				hits.Add(1)
+			}
+		})
+		cache.recordLookups(int(hits.Load()), len(fonts)-int(hits.Load()))
+		msg := previewsReloadedMsg{target, make(map[string]string, len(fonts))}
+		for i, f := range fonts {
+			msg.previews[f.Path] = previews[i]
+		}
+		return msg
+	}
+}
+
+// storeReloadedPreviews puts reloaded previews back unless the text or width
+// changed meanwhile.
+func (m model) storeReloadedPreviews(msg previewsReloadedMsg) {
+	for path, preview := range msg.previews {
+		m.previews.doneLoading(path)
+		if msg.target == m.previewed {
+			m.previews.put(path, preview)
+		}
+	}
+}
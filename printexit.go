@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+// --- Print on Exit ---
+// "Print" in the output menu quits and prints the render to stdout once the
+// alt screen is gone, so it stays in the scrollback; --print-on-exit does the
+// same with the last full render whenever the session ends. With stdout
+// redirected, as in `fontlet --print-on-exit | tee banner.txt`, the TUI draws
+// on the terminal instead and only the banner goes down the pipe. It keeps
+// the filter's colors only where stdout shows colors.
+
+// printAndQuit ends the session to print the render.
+func (m model) printAndQuit() (model, tea.Cmd) {
+	m.printOnExit = true
+	m.unsaved = false // Printed is as good as saved
+	m.logAction("Printed %s on exit", m.selectedFontMeta.Name)
+	return m, tea.Quit
+}
+
+// writeOnExit prints the last full render if the session asked for it.
+func writeOnExit(m model, stdout io.Writer) error {
+	if !m.printOnExit || m.fullFigletOutput == "" {
+		return nil
+	}
+	banner := m.outputText()
+	if !stdoutColors {
+		banner = stripANSI(banner)
+	}
+	if !strings.HasSuffix(banner, "\n") {
+		banner += "\n"
+	}
+	_, err := io.WriteString(stdout, banner)
+	return err
+}
+
+// terminalOut is the terminal the TUI draws on, for escapes sent around it
+// like the bell.
+var terminalOut io.Writer = os.Stdout
+
+// ttyForTUI opens the terminal for the TUI to draw on when stdout is
+// redirected, with the styles and terminalOut following it. It's nil when
+// stdout is the terminal or there is no terminal to open.
+func ttyForTUI() *os.File {
+	if term.IsTerminal(os.Stdout.Fd()) {
+		return nil
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil
+	}
+	useTerminalColors(tty)
+	terminalOut = tty
+	return tty
+}
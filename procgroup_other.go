@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os/exec"
+
+// ownProcessGroup leaves cmd as it is where there are no process groups;
+// cancelling its context kills the process.
+func ownProcessGroup(*exec.Cmd) {}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Loading Progress ---
+// Loading screens say which phase they're waiting for.
+
+// progressView names what the loading state waits for, e.g. "Scanning fonts…".
+func (m model) progressView() string {
+	switch m.state {
+	case stateInitialLoading:
+		return "Scanning fonts…"
+	case stateGeneratingFullOutput:
+		return "Rendering output…"
+	}
+	return ""
+}
+
+// --- Streamed Previews ---
+// The font list shows up as soon as the text is entered. Previews render in
+// the background (see streamPreviews) and arrive one message per font, each
+// replacing a placeholder in the list, so browsing and choosing can start
+// right away. The list title counts them, e.g. "Available Fonts (42/300
+// rendered)". Results go through a channel buffered for every font, so
+// rendering never waits for the UI. A stream superseded by newer text is
+// canceled: its workers skip the fonts left, so quick edits don't pile up
+// pools of renders.
+
+const pendingPreview = "(rendering…)"
+
+type previewProgress struct {
+	done    atomic.Int64
+	total   int
+	target  previewTarget
+	results chan previewRenderedMsg
+	cancel  context.CancelFunc // Stops the stream's workers
+}
+
+// previewRenderedMsg carries one font's finished preview.
+type previewRenderedMsg struct {
+	progress      *previewProgress
+	path, preview string
+}
+
+// previewsDoneMsg follows the last previewRenderedMsg of a stream.
+type previewsDoneMsg struct{ progress *previewProgress }
+
+// next waits for the next finished preview.
+func (p *previewProgress) next() tea.Cmd {
+	return func() tea.Msg {
+		if msg, ok := <-p.results; ok {
+			return msg
+		}
+		return previewsDoneMsg{p}
+	}
+}
+
+// setPreview stores a finished preview, which the list draws from there.
+func (m model) setPreview(path, preview string) model {
+	m.previews.put(path, preview)
+	m.fontList.Title = m.fontListTitle()
+	return m
+}
+
+// fontListTitle counts previews while they're streaming.
+func (m model) fontListTitle() string {
+	switch p := m.previewProgress; {
+	case m.highlightMode():
+		return "Available Fonts"
+	case p != nil:
+		return fmt.Sprintf("Available Fonts (%d/%d rendered)", p.done.Load(), p.total)
+	}
+	return "Available Fonts (with Previews)"
+}
+
+// --- Completion Bell ---
+// Rendering thousands of previews or saving a huge export can take long
+// enough to switch to another window meanwhile. When one took bell_after
+// seconds or more, the terminal bell rings as it finishes; most terminals
+// flag the tab or window, or flash.
+
+const defaultBellAfter = 10 // Seconds
+
+// bellAfter is how long an operation must take to ring the bell, 0 for never.
+func (c config) bellAfter() time.Duration {
+	switch {
+	case c.BellAfter < 0:
+		return 0
+	case c.BellAfter == 0:
+		return defaultBellAfter * time.Second
+	}
+	return time.Duration(c.BellAfter) * time.Second
+}
+
+// bellIfSlow rings the bell if the operation started at m.busySince took long enough.
+func (m model) bellIfSlow() tea.Cmd {
+	after := m.cfg.bellAfter()
+	if after == 0 || m.busySince.IsZero() || time.Since(m.busySince) < after {
+		return nil
+	}
+	return func() tea.Msg {
+		fmt.Fprint(terminalOut, "\a")
+		return nil
+	}
+}
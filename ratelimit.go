@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Rate Limiting ---
+// A token bucket per client IP: each client may make `burst` requests at
+// once, refilled at `perMinute`. Buckets that have been full for a while are
+// dropped so the map doesn't grow with every address ever seen.
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	perMinute float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(perMinute, burst int) *rateLimiter {
+	return &rateLimiter{perMinute: float64(perMinute), burst: float64(max(burst, 1)), buckets: map[string]*tokenBucket{}}
+}
+
+// allow takes a token from ip's bucket, or reports how long until one is available.
+func (l *rateLimiter) allow(ip string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) > time.Minute {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[ip]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[ip] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Minutes()*l.perMinute)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.perMinute * float64(time.Minute))
+	}
+	b.tokens--
+	return true, 0
+}
+
+func (l *rateLimiter) sweep(now time.Time) {
+	refill := time.Duration(l.burst / l.perMinute * float64(time.Minute))
+	for ip, b := range l.buckets {
+		if now.Sub(b.last) > refill {
+			delete(l.buckets, ip)
+		}
+	}
+	l.lastSweep = now
+}
+
+// clientIP is the address rate limits apply to. Behind a reverse proxy every
+// request comes from the proxy, so with trustProxy the address the proxy
+// appended to X-Forwarded-For is used instead (earlier entries can be forged
+// by the client, including whole extra X-Forwarded-For headers).
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if fwd := r.Header.Values("X-Forwarded-For"); len(fwd) > 0 {
+			hops := strings.Split(fwd[len(fwd)-1], ",")
+			return strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	type call struct {
+		ip   string
+		at   time.Duration // After start
+		want bool
+	}
+	tests := []struct {
+		name             string
+		perMinute, burst int
+		calls            []call
+	}{
+		{"burst then refused", 60, 2, []call{
+			{"a", 0, true}, {"a", 0, true}, {"a", 0, false},
+		}},
+		{"refills at the rate", 60, 1, []call{
+			{"a", 0, true}, {"a", 500 * time.Millisecond, false}, {"a", time.Second, true},
+		}},
+		{"refill capped at burst", 60, 2, []call{
+			{"a", 0, true}, {"a", time.Hour, true}, {"a", time.Hour, true}, {"a", time.Hour, false},
+		}},
+		{"per ip", 60, 1, []call{
+			{"a", 0, true}, {"b", 0, true}, {"a", 0, false}, {"b", 0, false},
+		}},
+		{"zero burst is one", 60, 0, []call{
+			{"a", 0, true}, {"a", 0, false},
+		}},
+		{"refused calls don't drain", 60, 1, []call{
+			{"a", 0, true}, {"a", 0, false}, {"a", 0, false}, {"a", time.Second, true},
+		}},
+	}
+	for _, tt := range tests {
+		l := newRateLimiter(tt.perMinute, tt.burst)
+		for i, c := range tt.calls {
+			ok, wait := l.allow(c.ip, start.Add(c.at))
+			if ok != c.want {
+				t.Errorf("%s: call %d from %s at %v allowed %v, want %v", tt.name, i, c.ip, c.at, ok, c.want)
+			}
+			if !ok && (wait <= 0 || wait > time.Minute/time.Duration(tt.perMinute)) {
+				t.Errorf("%s: call %d wait %v, want up to %v", tt.name, i, wait, time.Minute/time.Duration(tt.perMinute))
+			}
+		}
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newRateLimiter(60, 5)
+	l.allow("a", start)
+	l.allow("b", start.Add(2*time.Minute)) // Sweeps a, full again after 5s
+	if _, ok := l.buckets["a"]; ok || len(l.buckets) != 1 {
+		t.Errorf("after a sweep buckets = %v, want only b", l.buckets)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remote     string
+		forwarded  []string
+		trustProxy bool
+		want       string
+	}{
+		{"remote", "192.0.2.1:5000", nil, false, "192.0.2.1"},
+		{"ipv6 remote", "[2001:db8::1]:5000", nil, false, "2001:db8::1"},
+		{"untrusted header ignored", "192.0.2.1:5000", []string{"198.51.100.7"}, false, "192.0.2.1"},
+		{"proxy", "10.0.0.1:5000", []string{"198.51.100.7"}, true, "198.51.100.7"},
+		{"forged earlier hop", "10.0.0.1:5000", []string{"6.6.6.6, 198.51.100.7"}, true, "198.51.100.7"},
+		{"forged extra header", "10.0.0.1:5000", []string{"6.6.6.6", "198.51.100.7"}, true, "198.51.100.7"},
+		{"proxy without header", "10.0.0.1:5000", nil, true, "10.0.0.1"},
+		{"no port", "192.0.2.1", nil, false, "192.0.2.1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tt.remote
+		for _, v := range tt.forwarded {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if got := clientIP(r, tt.trustProxy); got != tt.want {
+			t.Errorf("%s: clientIP = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// --- Render Cache ---
+// Serve mode keeps recent responses in memory so repeated requests (the same
+// MOTD fetched by many machines) don't run figlet again. The least recently
+// used entry is evicted when full. With a disk dir, entries also survive
+// restarts, stored like warmed previews (see previewCache) and evicted from
+// disk once they take more than the dir's budget.
+
+type renderCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Front is most recently used; values are *renderCacheEntry
+	entries  map[string]*list.Element
+	disk     previewCache // Zero value when not persisting; every lookup misses
+}
+
+type renderCacheEntry struct {
+	key, output string
+}
+
+func newRenderCache(capacity int, diskDir string, diskBudget int64) *renderCache {
+	return &renderCache{capacity: capacity, order: list.New(), entries: map[string]*list.Element{}, disk: newPreviewCache(diskDir, diskBudget)}
+}
+
+// renderKey identifies a response: the font file as of its last edit, text,
+// width and filter, and the figlet binary.
+func renderKey(font fontMetadata, text string, width int, filter, figletCmdPath string) string {
+	sum := sha256.Sum256([]byte(previewKey(font, text, width, figletCmdPath) + "\x00" + filter))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *renderCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		c.mu.Unlock()
+		return el.Value.(*renderCacheEntry).output, true
+	}
+	c.mu.Unlock()
+	output, ok := c.disk.get(key)
+	if ok {
+		c.add(key, output)
+	}
+	return output, ok
+}
+
+// put stores a response. Persisting is best effort: a full or read-only disk
+// only costs the next restart some renders.
+func (c *renderCache) put(key, output string) {
+	c.add(key, output)
+	if c.disk.dir != "" {
+		c.disk.put(key, output)
+	}
+}
+
+func (c *renderCache) add(key, output string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&renderCacheEntry{key: key, output: output})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*renderCacheEntry).key)
+	}
+}
+
+func (c *renderCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Saving ---
+// Files are written in chunks by a command while a ticker redraws the
+// progress, so big exports don't freeze the screen and Esc can stop them.
+// Writes go to a temp file that is renamed over the target when complete, so
+// an interrupted save never leaves a truncated banner behind.
+
+const saveChunkSize = 256 << 10
+
+// saveJob is shared between the writing command and the model.
+type saveJob struct {
+	path     string
+	mode     fs.FileMode
+	total    int64
+	written  atomic.Int64
+	canceled atomic.Bool
+}
+
+type saveProgressMsg struct{ job *saveJob }
+type saveCanceledMsg struct{ path string }
+
+var errSaveCanceled = errors.New("save canceled")
+
+// startSave switches to the progress screen and starts writing content to path.
+func (m model) startSave(path, content string) (model, tea.Cmd) {
+	job := &saveJob{path: path, mode: m.saveMode(), total: int64(len(content))}
+	m.saving = job
+	m.busySince = time.Now()
+	m.state = stateSaving
+	m.statusMessage = ""
+	m.textInput.Blur()
+	return m, tea.Batch(m.spinner.Tick, saveFileCmd(job, content), saveProgressTick(job))
+}
+
+// dirExists reports whether dir is there to save into. Errors other than
+// not existing (e.g. permissions) count as existing, so the write reports them.
+func dirExists(dir string) bool {
+	_, err := os.Stat(dir)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// saveMode is the configured file mode, executable for shell scripts.
+func (m model) saveMode() fs.FileMode {
+	mode := m.cfg.fileMode()
+	if exportFormats[m.exportIndex].Script != nil && m.cfg.ScriptExec {
+		mode |= (mode & 0444) >> 2 // Execute wherever it's readable
+	}
+	return mode
+}
+
+func saveFileCmd(job *saveJob, content string) tea.Cmd {
+	return func() tea.Msg {
+		err := writeChunked(job, content)
+		if errors.Is(err, errSaveCanceled) {
+			return saveCanceledMsg{path: job.path}
+		}
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to save file '%s': %w", job.path, err)}
+		}
+		return fileSavedMsg{path: job.path}
+	}
+}
+
+func writeChunked(job *saveJob, content string) error {
+	return writeAtomic(job.path, job.mode, func(f *os.File) error {
+		for len(content) > 0 {
+			if job.canceled.Load() {
+				return errSaveCanceled
+			}
+			n, err := f.WriteString(content[:min(saveChunkSize, len(content))])
+			job.written.Add(int64(n))
+			if err != nil {
+				return err
+			}
+			content = content[n:]
+		}
+		return nil
+	})
+}
+
+// writeAtomic lets write fill a temp file next to path, then syncs it and
+// renames it into place. A new file gets perm (less the umask), an existing
+// one keeps its permissions but becomes executable if perm is. On any error
+// the temp file is removed and path is left as it was.
+func writeAtomic(path string, perm fs.FileMode, write func(f *os.File) error) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target // Replace what the link points to, not the link
+	}
+	info, statErr := os.Stat(path)
+	f, err := createTemp(path, perm)
+	if err != nil {
+		return err
+	}
+	tempFiles.Store(f.Name(), true) // Removed by shutdown if fontlet quits meanwhile
+	defer tempFiles.Delete(f.Name())
+	fail := func(err error) error {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := write(f); err != nil {
+		return fail(err)
+	}
+	if statErr == nil {
+		mode := info.Mode().Perm()
+		if perm&0111 != 0 {
+			mode |= (mode & 0444) >> 2
+		}
+		if err := f.Chmod(mode); err != nil {
+			return fail(err)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return fail(fmt.Errorf("fsync: %w", err))
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("rename into place: %w", err)
+	}
+	return nil
+}
+
+// createTemp is os.CreateTemp with a mode, which CreateTemp fixes at 0600.
+func createTemp(path string, perm fs.FileMode) (*os.File, error) {
+	for range 100 {
+		name := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.%d.tmp", filepath.Base(path), rand.Uint32()))
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+		if !errors.Is(err, fs.ErrExist) {
+			return f, err
+		}
+	}
+	return nil, fmt.Errorf("no free temp file name next to %s", path)
+}
+
+func saveProgressTick(job *saveJob) tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg { return saveProgressMsg{job} })
+}
+
+// progressView is e.g. "[#####-----] 1.2 MiB of 2.4 MiB (50%)".
+func (j *saveJob) progressView(width int) string {
+	written := j.written.Load()
+	fraction := 1.0
+	if j.total > 0 {
+		fraction = float64(written) / float64(j.total)
+	}
+	barWidth := max(min(width-40, 40), 10)
+	filled := int(fraction * float64(barWidth))
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", barWidth-filled)
+	return fmt.Sprintf("[%s] %s of %s (%d%%)", bar, formatBytes(written), formatBytes(j.total), int(fraction*100))
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
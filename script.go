@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --- Session Scripts ---
+// Instead of the banner itself, the "shell script" format saves a POSIX sh
+// script that regenerates it with figlet, so banners checked into repos can
+// document where they came from. Filters, the footer and centering are
+// fontlet's own, so the format is refused while any of them is on rather
+// than save a script printing something else than the banner.
+
+// renderSpec is everything that went into the current banner.
+type renderSpec struct {
+	Text     string
+	FontName string
+	FontPath string
+	Control  string // Path of the font's control file, if it needs one
+	Width    int
+	Opts     saveOptions
+}
+
+func (m model) renderSpec() renderSpec {
+	spec := renderSpec{
+		Text:     m.renderText(),
+		FontName: m.selectedFontMeta.Name,
+		FontPath: m.selectedFontMeta.Path,
+		Control:  m.selectedFontMeta.ControlPath,
+		Width:    m.fullRenderWidth(),
+		Opts:     m.saveOpts,
+	}
+	return spec
+}
+
+// scriptUnsupported explains why the current banner can't be saved as a
+// script, nil if it can.
+func (m model) scriptUnsupported() error {
+	if isTOIletFont(m.selectedFontMeta.Path) {
+		return fmt.Errorf("shell scripts run figlet, which can't read TOIlet (.tlf) fonts like %s, save in another format", m.selectedFontMeta.Name)
+	}
+	var active []string
+	if name := outputFilters[m.filterIndex].Name; name != outputFilters[0].Name {
+		active = append(active, "the "+name+" filter")
+	}
+	if m.saveOpts.Footer && m.cfg.Footer.Text != "" {
+		active = append(active, "the footer")
+	}
+	if m.saveOpts.Center && m.cfg.CenterWidth > 0 {
+		active = append(active, "centering")
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	return fmt.Errorf("shell scripts regenerate figlet's output alone, turn off %s or save in another format", joinAnd(active))
+}
+
+// joinAnd lists items as "a", "a and b" or "a, b and c".
+func joinAnd(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// shellQuote quotes s for sh, where nothing inside single quotes is special.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// commentLine keeps s on one line of a comment, where a line break would
+// end the comment and run the rest as a command.
+func commentLine(s string) string {
+	return strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(s)
+}
+
+// exportScript writes the script. Save options become pipeline stages so the
+// script's output matches what would have been saved; the script file itself
+// always has LF line endings and no BOM, or sh couldn't run it.
+func exportScript(spec renderSpec) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Generated by fontlet: regenerates a banner with figlet.\n#\n")
+	fmt.Fprintf(&b, "#   text:   %s\n", commentLine(spec.Text))
+	fmt.Fprintf(&b, "#   font:   %s (%s)\n", commentLine(spec.FontName), commentLine(spec.FontPath))
+	fmt.Fprintf(&b, "#   width:  %d\n", spec.Width)
+	b.WriteString("#\n# Set FIGLET_FONT where the font is installed somewhere else.\n")
+	b.WriteString("set -e\n\n")
+	fmt.Fprintf(&b, "font=${FIGLET_FONT:-%s}\n", shellQuote(spec.FontPath))
+
+	if spec.Opts.BOM {
+		b.WriteString("printf '\\357\\273\\277'\n")
+	}
+	figlet := `figlet -f "$font"`
+	if spec.Control != "" {
+		figlet += " -C " + shellQuote(spec.Control)
+	}
+	stages := []string{fmt.Sprintf(`%s -w %d %s`, figlet, spec.Width, shellQuote(spec.Text))}
+	if spec.Opts.CRLF {
+		stages = append(stages, `awk '{ printf "%s\r\n", $0 }'`)
+	}
+	b.WriteString(strings.Join(stages, " |\n\t"))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportScriptCommentsStayComments(t *testing.T) {
+	script := exportScript(renderSpec{
+		Text:     "Hi\nrm -rf ~\r\n",
+		FontName: "mini",
+		FontPath: "/fonts/mini\n.flf",
+		Width:    80,
+	})
+	header, _, _ := strings.Cut(script, "set -e\n")
+	for _, line := range strings.Split(strings.TrimSuffix(header, "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			t.Errorf("header line %q isn't a comment", line)
+		}
+	}
+}
+
+func TestScriptUnsupported(t *testing.T) {
+	braille := 0
+	for i, f := range outputFilters {
+		if f.Name == "braille" {
+			braille = i
+		}
+	}
+	tests := []struct {
+		name string
+		m    model
+		want string // Empty when a script can be saved
+	}{
+		{"plain", model{}, ""},
+		{"footer configured but off", model{cfg: config{Footer: footerConfig{Text: "{date}"}}}, ""},
+		{"filter", model{filterIndex: braille}, "turn off the braille filter or"},
+		{"footer", model{cfg: config{Footer: footerConfig{Text: "{date}"}}, saveOpts: saveOptions{Footer: true}}, "turn off the footer or"},
+		{"all", model{filterIndex: braille, cfg: config{Footer: footerConfig{Text: "x"}, CenterWidth: 80}, saveOpts: saveOptions{Footer: true, Center: true}},
+			"turn off the braille filter, the footer and centering or"},
+	}
+	for _, tt := range tests {
+		err := tt.m.scriptUnsupported()
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s: error %v, want one containing %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// --- Scripted Mode ---
+// `fontlet --font slant --text "Deploy OK" --width 100 --output banner.txt`
+// renders without starting the TUI, for shell scripts, Makefiles and CI.
+// --font or --output selects it; the font takes the same names as the
+// commands (fuzzy matches, "random", "auto"). Without --output the banner goes
+// to stdout. --text alone just starts the TUI with that text. The text can
+// also come from stdin or a file, see pipedtext.go.
+
+type scriptedOptions struct {
+	font, text, output string
+	width              int // 0 follows the terminal, or 80 when redirected
+}
+
+// scripted reports whether the flags ask for a render without the TUI.
+func (o scriptedOptions) scripted() bool {
+	return o.font != "" || o.output != ""
+}
+
+func runScripted(opts scriptedOptions, stdout io.Writer) error {
+	if opts.text == "" {
+		return fmt.Errorf("--font and --output render without the TUI and need --text, -i or piped text")
+	}
+	if opts.font == "" {
+		opts.font = "standard"
+	}
+	if opts.width < 0 {
+		return fmt.Errorf("--width must be positive, or 0 for the terminal's width")
+	}
+	if opts.width == 0 {
+		opts.width = terminalWidth()
+	}
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	font, err := pickFontFor(env, opts.font, opts.text, opts.width, newRand(globalOptions.seed, globalOptions.seeded))
+	if err != nil {
+		return err
+	}
+	output, err := runFiglet(env.figletCmdPath, font.Path, opts.text, opts.width)
+	if err != nil {
+		return err
+	}
+	if opts.output == "" || opts.output == "-" {
+		_, err = io.WriteString(stdout, output)
+		return err
+	}
+	err = writeAtomic(opts.output, env.cfg.fileMode(), func(f *os.File) error {
+		_, err := io.WriteString(f, output)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.output, err)
+	}
+	return nil
+}
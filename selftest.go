@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// --- Self-Test ---
+// `fontlet selftest` renders known texts in the built-in stand-in fonts (see
+// fakefiglet.go), runs the results through the filters, the layout engine and
+// every export format, and compares each output with a golden file compiled
+// into the binary. Nothing depends on figlet, the config or the terminal, so
+// a mismatch on any platform is a regression. After an intended change,
+// `fontlet selftest --update selftest` rewrites the golden files in the
+// source tree for review.
+
+//go:embed selftest/*.golden
+var goldenFiles embed.FS
+
+type selftestCase struct {
+	Name string // Also the golden file's name, without .golden
+	Run  func() (string, error)
+}
+
+// selftestRender renders text in a stand-in font.
+func selftestRender(font, text string, width int) (string, error) {
+	return runFiglet(fakeFigletCmd, font+".flf", text, width)
+}
+
+// selftestGlyphs are glyphs of figlet's standard font, enough for "Hello, World!".
+var selftestGlyphs = map[rune][]string{
+	' ': {" $", " $", " $", " $", " $", " $"},
+	'!': {"  _ ", " | |", " | |", " |_|", " (_)", "    "},
+	',': {"    ", "    ", "    ", "  _ ", " ( )", " |/ "},
+	'H': {"  _   _ ", " | | | |", " | |_| |", " |  _  |", " |_| |_|", "        "},
+	'W': {" __        __", ` \ \      / /`, `  \ \ /\ / / `, `   \ V  V /  `, `    \_/\_/   `, "             "},
+	'd': {"      _ ", "   __| |", "  / _` |", " | (_| |", `  \__,_|`, "        "},
+	'e': {"       ", "   ___ ", `  / _ \`, ` |  __/`, `  \___|`, "       "},
+	'l': {"  _ ", " | |", " | |", " | |", " |_|", "    "},
+	'o': {"        ", "   ___  ", `  / _ \ `, " | (_) |", `  \___/ `, "        "},
+	'r': {"       ", "  _ __ ", " | '__|", " | |   ", " |_|   ", "       "},
+}
+
+// selftestFIGfont is a FIGfont with selftestGlyphs and the given full
+// layout, for the built-in renderer.
+func selftestFIGfont(fullLayout int) (*figFont, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "flf2a$ 6 5 16 15 1 0 %d\nSome glyphs of standard.flf by Glenn Chappell & Ian Chai\n", fullLayout)
+	for i := 0; i < flfRequiredChars; i++ {
+		rows := selftestGlyphs[rune(' '+i)]
+		for row := 0; row < 6; row++ {
+			if rows != nil {
+				b.WriteString(rows[row])
+			}
+			b.WriteString("@")
+			if row == 5 {
+				b.WriteString("@")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return parseFIGfont(strings.NewReader(b.String()))
+}
+
+// selftestSlug turns an export format's name into part of a file name.
+var selftestSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+func selftestCases() []selftestCase {
+	var cases []selftestCase
+	for _, f := range fakeFonts {
+		cases = append(cases, selftestCase{"render-" + f.Name, func() (string, error) {
+			return selftestRender(f.Name, "Hello, World!", 80)
+		}})
+	}
+	cases = append(cases,
+		selftestCase{"render-wrapped", func() (string, error) { return selftestRender("fake-block", "fontlet wraps long text", 24) }},
+		selftestCase{"measure", func() (string, error) {
+			banner, err := selftestRender("fake-tall", "Hi there", 80)
+			size := measureBanner(banner)
+			return fmt.Sprintf("%d x %d\n", size.Width, size.Height), err
+		}},
+	)
+	builtin := func(fullLayout int, text string, width int) func() (string, error) {
+		return func() (string, error) {
+			font, err := selftestFIGfont(fullLayout)
+			if err != nil {
+				return "", err
+			}
+			return font.render(context.Background(), text, width)
+		}
+	}
+	cases = append(cases,
+		selftestCase{"builtin-smush", builtin(24463, "Hello, World!", 80)},
+		selftestCase{"builtin-kern", builtin(smushKern, "Hello, World!", 80)},
+		selftestCase{"builtin-full-width", builtin(0, "Hello, World!", 80)},
+		selftestCase{"builtin-wrapped", builtin(24463, "Hello, World!", 40)},
+	)
+	for _, f := range outputFilters[1:] {
+		cases = append(cases, selftestCase{"filter-" + f.Name, func() (string, error) {
+			banner, err := selftestRender("fake-block", "Hi!", 80)
+			return f.Apply(banner), err
+		}})
+	}
+	layout := func(direction layoutDirection, align layoutAlign) func() (string, error) {
+		return func() (string, error) {
+			title, err := selftestRender("fake-tall", "Title", 80)
+			if err != nil {
+				return "", err
+			}
+			sub, err := selftestRender("fake-thin", "sub", 80)
+			return composeBlocks([]string{title, makeDivider("─", 10), sub}, direction, align, 1), err
+		}
+	}
+	cases = append(cases,
+		selftestCase{"layout-column-center", layout(layoutColumn, alignCenter)},
+		selftestCase{"layout-row-end", layout(layoutRow, alignEnd)},
+		selftestCase{"center-width", func() (string, error) {
+			banner, err := selftestRender("fake-wide", "ok", 80)
+			return centerBlock(banner, 40), err
+		}},
+	)
+
+	// A backslash and a colored copy exercise escaping and color conversion
+	cfg := defaultConfig()
+	for _, f := range exportFormats {
+		name := "export-" + strings.Trim(selftestSlug.ReplaceAllString(strings.ToLower(f.Name), "-"), "-")
+		cases = append(cases, selftestCase{name, func() (string, error) {
+			if f.Script != nil {
+				return f.Script(renderSpec{Text: `Hi\`, FontName: "fake-block", FontPath: "/usr/share/figlet/fake-block.flf", Width: 80}), nil
+			}
+			banner, err := selftestRender("fake-block", `Hi\`, 80)
+			if err != nil {
+				return "", err
+			}
+			if f.Name == "IRC (mIRC colors)" || f.Name == "HTML (pre block)" || f.Name == "PNG image" {
+				lines := bannerLines(banner)
+				for i, line := range lines {
+					lines[i] = "\x1b[38;5;208m" + line + "\x1b[0m"
+				}
+				banner = strings.Join(lines, "\n") + "\n"
+			}
+			return f.Export(banner, cfg)
+		}})
+	}
+	cases = append(cases, selftestCase{"encode-crlf-bom", func() (string, error) {
+		banner, err := selftestRender("fake-block", "Hi", 80)
+		return saveOptions{CRLF: true, BOM: true}.encode(banner), err
+	}})
+	return cases
+}
+
+// firstDifference describes where got first differs from want.
+func firstDifference(want, got string) string {
+	wantLines, gotLines := strings.SplitAfter(want, "\n"), strings.SplitAfter(got, "\n")
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d: want %q, got %q", i+1, w, g)
+		}
+	}
+	return "outputs differ"
+}
+
+func runSelftest(args []string, stdout io.Writer) error {
+	fs := newFlagSet("selftest")
+	update := fs.String("update", "", "write the current outputs as golden files to this directory instead of comparing")
+	run := fs.String("run", "", "only run cases whose name contains this")
+	verbose := fs.Bool("v", false, "list passing cases too")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("selftest takes no arguments")
+	}
+	if *update != "" {
+		if err := os.MkdirAll(*update, 0755); err != nil {
+			return err
+		}
+	}
+
+	ran, failed := 0, 0
+	for _, c := range selftestCases() {
+		if !strings.Contains(c.Name, *run) {
+			continue
+		}
+		ran++
+		got, err := c.Run()
+		if err != nil {
+			failed++
+			fmt.Fprintf(stdout, "FAIL %s: %v\n", c.Name, err)
+			continue
+		}
+		file := c.Name + ".golden"
+		if *update != "" {
+			if err := os.WriteFile(filepath.Join(*update, file), []byte(got), 0644); err != nil {
+				return err
+			}
+			continue
+		}
+		want, err := goldenFiles.ReadFile("selftest/" + file)
+		switch {
+		case err != nil:
+			failed++
+			fmt.Fprintf(stdout, "FAIL %s: no golden file (run selftest --update selftest from the source tree)\n", c.Name)
+		case string(want) != got:
+			failed++
+			fmt.Fprintf(stdout, "FAIL %s: %s\n", c.Name, firstDifference(string(want), got))
+		case *verbose:
+			fmt.Fprintf(stdout, "ok   %s\n", c.Name)
+		}
+	}
+
+	if *update != "" {
+		fmt.Fprintf(stdout, "Wrote %d golden files to %s\n", ran-failed, shortenHome(*update))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d self-tests failed", failed, ran)
+	}
+	if *update == "" {
+		fmt.Fprintf(stdout, "All %d self-tests passed\n", ran)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+// TestSelftest runs `fontlet selftest`'s cases against their golden files.
+func TestSelftest(t *testing.T) {
+	for _, c := range selftestCases() {
+		t.Run(c.Name, func(t *testing.T) {
+			got, err := c.Run()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := goldenFiles.ReadFile("selftest/" + c.Name + ".golden")
+			if err != nil {
+				t.Fatalf("no golden file (run fontlet selftest --update selftest): %v", err)
+			}
+			if string(want) != got {
+				t.Error(firstDifference(string(want), got))
+			}
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+// --- Serve Mode ---
+// `fontlet serve` is a small HTTP render service, e.g. for MOTDs fetched by
+// many machines:
+//
+//	GET /render?font=slant&text=Hi&width=60&filter=braille  the banner as text/plain
+//	GET /fonts                                              the fonts as JSON, as in --stdio's list-fonts
+//	GET /healthz                                            "ok" once fonts are loaded
+//	GET /metrics                                            request and render counters, Prometheus text format
+//	GET /                                                   a live preview page, rendering over the /live WebSocket
+//
+// It shuts down gracefully on SIGINT/SIGTERM so it behaves behind a reverse
+// proxy or process supervisor. Renders are capped in text length, width and
+// time, and rate limited per client, so one client can't tie up the host;
+// --token or --basic-auth keep it private on a shared network.
+
+type server struct {
+	env     cliEnv
+	rng     *rand.Rand // For font=random; safe to share, see lockedSource
+	metrics *serveMetrics
+	limits  serveLimits
+	limiter *rateLimiter // nil when rate limiting is off
+	cache   *renderCache // nil when caching is off
+	auth    serveAuth
+	sockets chan struct{} // A slot per open /live WebSocket, --max-sockets of them
+}
+
+type serveLimits struct {
+	maxText       int // In characters
+	maxWidth      int
+	renderTimeout time.Duration
+	trustProxy    bool // Rate limit by X-Forwarded-For, see clientIP
+}
+
+// lockedSource lets concurrent handlers share one (possibly seeded) generator.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func runServe(args []string, stdout io.Writer) error {
+	fs := newFlagSet("serve")
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	rate := fs.Int("rate", 60, "renders per minute allowed per client IP (0 for no limit)")
+	burst := fs.Int("burst", 10, "renders a client may make at once before --rate applies")
+	maxText := fs.Int("max-text", 256, "longest text to render, in characters")
+	maxWidth := fs.Int("max-width", 400, "widest render allowed, in columns")
+	renderTimeout := fs.Duration("render-timeout", 5*time.Second, "stop figlet runs taking longer than this")
+	trustProxy := fs.Bool("trust-proxy", false, "rate limit by the client address a reverse proxy puts in X-Forwarded-For")
+	cacheSize := fs.Int("cache-size", 1000, "renders kept in memory for repeated requests (0 for no caching)")
+	cacheDir := fs.String("cache-dir", "", "also keep cached renders in this directory, so they survive restarts")
+	cacheDirMB := fs.Int("cache-dir-mb", 100, "MiB the renders in --cache-dir may take, the least recently used are evicted beyond it")
+	maxSockets := fs.Int("max-sockets", 100, "live preview WebSockets open at once, more are refused with 503")
+	token := fs.String("token", os.Getenv("FONTLET_SERVE_TOKEN"), "require this bearer token (default $FONTLET_SERVE_TOKEN, which keeps it out of ps)")
+	basicAuth := fs.String("basic-auth", os.Getenv("FONTLET_SERVE_BASIC_AUTH"), "require these basic auth credentials, as USER:PASSWORD (default $FONTLET_SERVE_BASIC_AUTH)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	auth := serveAuth{token: *token}
+	if *basicAuth != "" {
+		var ok bool
+		if auth.user, auth.password, ok = strings.Cut(*basicAuth, ":"); !ok || auth.user == "" || auth.password == "" {
+			return fmt.Errorf("--basic-auth must be USER:PASSWORD")
+		}
+	}
+	if *maxText <= 0 || *maxWidth <= 0 || *renderTimeout <= 0 || *cacheDirMB <= 0 || *maxSockets <= 0 {
+		return fmt.Errorf("--max-text, --max-width, --render-timeout, --cache-dir-mb and --max-sockets must be positive")
+	}
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	seed := globalOptions.seed
+	if !globalOptions.seeded {
+		seed = uint64(time.Now().UnixNano())
+	}
+	s := &server{
+		env:     env,
+		rng:     rand.New(&lockedSource{src: rand.NewPCG(seed, 0)}),
+		metrics: newServeMetrics(),
+		auth:    auth,
+		sockets: make(chan struct{}, *maxSockets),
+		limits:  serveLimits{maxText: *maxText, maxWidth: *maxWidth, renderTimeout: *renderTimeout, trustProxy: *trustProxy},
+	}
+	if *rate > 0 {
+		s.limiter = newRateLimiter(*rate, *burst)
+	}
+	if *cacheSize > 0 {
+		s.cache = newRenderCache(*cacheSize, *cacheDir, int64(*cacheDirMB)<<20)
+	} else if *cacheDir != "" {
+		return fmt.Errorf("--cache-dir needs a --cache-size above 0")
+	}
+	srv := &http.Server{Addr: *addr, Handler: s.routes(), ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	fmt.Fprintf(stdout, "Serving %d fonts on http://%s\n", len(env.fonts), *addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /render", s.handleRender)
+	mux.HandleFunc("GET /fonts", s.handleFonts)
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("GET /live", s.handleLive)
+	mux.HandleFunc("GET /{$}", s.handleWebUI)
+	return s.metrics.count(s.auth.require(mux))
+}
+
+func (s *server) handleRender(w http.ResponseWriter, r *http.Request) {
+	if !s.allow(w, r) {
+		return
+	}
+	q := r.URL.Query()
+	req := renderRequest{Font: q.Get("font"), Text: q.Get("text"), Filter: q.Get("filter")}
+	if v := q.Get("width"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, fmt.Sprintf("invalid width %q", v), http.StatusBadRequest)
+			return
+		}
+		req.Width = n
+	}
+	res, err := s.render(r.Context(), req)
+	var rerr renderError
+	if errors.As(err, &rerr) {
+		http.Error(w, rerr.msg, rerr.status)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Fontlet-Font", res.Font)
+	if res.cache != "" {
+		w.Header().Set("X-Fontlet-Cache", res.cache)
+	}
+	io.WriteString(w, res.Output)
+}
+
+// allow applies the per-client rate limit, answering 429 when it's exceeded.
+func (s *server) allow(w http.ResponseWriter, r *http.Request) bool {
+	ok, wait := s.allowIP(clientIP(r, s.limits.trustProxy))
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+	}
+	return ok
+}
+
+func (s *server) allowIP(ip string) (bool, time.Duration) {
+	if s.limiter == nil {
+		return true, 0
+	}
+	return s.limiter.allow(ip, time.Now())
+}
+
+// renderRequest is a render asked for at /render or over the live-preview socket.
+type renderRequest struct {
+	Font   string `json:"font"` // Font name, or "random"
+	Text   string `json:"text"`
+	Width  int    `json:"width,omitempty"` // Default 80
+	Filter string `json:"filter,omitempty"`
+}
+
+type renderResult struct {
+	Output string `json:"output,omitempty"`
+	Font   string `json:"font,omitempty"` // The font used, e.g. the one "random" picked
+	Error  string `json:"error,omitempty"`
+	cache  string // "hit" or "miss", empty when caching is off
+}
+
+// renderError is a failed render and the HTTP status it's answered with.
+type renderError struct {
+	status int
+	msg    string
+}
+
+func (e renderError) Error() string { return e.msg }
+
+func renderFailed(status int, format string, args ...any) renderError {
+	return renderError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+// render checks a request against the limits and renders it, from the cache
+// when possible. Errors are renderErrors.
+func (s *server) render(ctx context.Context, req renderRequest) (renderResult, error) {
+	if req.Text == "" || req.Font == "" {
+		return renderResult{}, renderFailed(http.StatusBadRequest, "render needs font and text parameters")
+	}
+	if n := utf8.RuneCountInString(req.Text); n > s.limits.maxText {
+		return renderResult{}, renderFailed(http.StatusRequestEntityTooLarge, "text is %d characters, the limit is %d", n, s.limits.maxText)
+	}
+	width := req.Width
+	if width <= 0 {
+		width = 80
+	}
+	if width > s.limits.maxWidth {
+		return renderResult{}, renderFailed(http.StatusBadRequest, "width %d is over the limit of %d", width, s.limits.maxWidth)
+	}
+	filter := outputFilters[0]
+	if req.Filter != "" {
+		found := false
+		for _, f := range outputFilters {
+			if f.Name == req.Filter {
+				filter, found = f, true
+			}
+		}
+		if !found {
+			return renderResult{}, renderFailed(http.StatusBadRequest, "unknown filter %q", req.Filter)
+		}
+	}
+	font, err := pickFont(s.env.fonts, req.Font, s.rng)
+	if err != nil {
+		return renderResult{}, renderFailed(http.StatusNotFound, "%v", err)
+	}
+
+	res := renderResult{Font: font.Name}
+	var key string
+	if s.cache != nil {
+		key = renderKey(font, req.Text, width, filter.Name, s.env.figletCmdPath)
+		if output, ok := s.cache.get(key); ok {
+			s.metrics.observeCache(true)
+			res.Output, res.cache = output, "hit"
+			return res, nil
+		}
+		s.metrics.observeCache(false)
+		res.cache = "miss"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.limits.renderTimeout)
+	defer cancel()
+	start := time.Now()
+	output, err := runFigletContext(ctx, s.env.figletCmdPath, font.Path, req.Text, width)
+	s.metrics.observeRender(time.Since(start))
+	if errors.Is(err, context.DeadlineExceeded) {
+		return renderResult{}, renderFailed(http.StatusServiceUnavailable, "render timed out")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, stderrError(fmt.Sprintf("render of %s failed: %v", font.Name, err))) // Details name server paths, so only the log gets them
+		return renderResult{}, renderFailed(http.StatusInternalServerError, "render failed")
+	}
+	res.Output = filter.Apply(output)
+	if s.cache != nil {
+		s.cache.put(key, res.Output)
+	}
+	return res, nil
+}
+
+func (s *server) handleFonts(w http.ResponseWriter, r *http.Request) {
+	fonts := make([]stdioFont, len(s.env.fonts))
+	for i, f := range s.env.fonts {
+		fonts[i] = stdioFont{Name: f.Name, Path: f.Path, Dir: f.Dir, Header: f.Header}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(fonts)
+}
+
+func (s *server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if len(s.env.fonts) == 0 {
+		http.Error(w, "no fonts", http.StatusServiceUnavailable)
+		return
+	}
+	io.WriteString(w, "ok\n")
+}
+
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	cached := 0
+	if s.cache != nil {
+		cached = s.cache.len()
+	}
+	s.metrics.write(w, len(s.env.fonts), cached)
+}
+
+// --- Serve Metrics ---
+// Counters exposed at /metrics. Paths outside the routes are counted as
+// "other" so scanners can't grow the label set without bound.
+
+type requestKey struct {
+	path string
+	code int
+}
+
+type serveMetrics struct {
+	mu            sync.Mutex
+	started       time.Time
+	requests      map[requestKey]int
+	renders       int
+	renderSeconds float64
+	cacheHits     int
+	cacheMisses   int
+}
+
+func newServeMetrics() *serveMetrics {
+	return &serveMetrics{started: time.Now(), requests: map[requestKey]int{}}
+}
+
+var servePaths = map[string]bool{"/": true, "/live": true, "/render": true, "/fonts": true, "/healthz": true, "/metrics": true}
+
+// statusRecorder remembers the status code a handler wrote.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the connection, for /live.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (m *serveMetrics) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		path := r.URL.Path
+		if !servePaths[path] {
+			path = "other"
+		}
+		m.mu.Lock()
+		m.requests[requestKey{path, rec.code}]++
+		m.mu.Unlock()
+	})
+}
+
+func (m *serveMetrics) observeRender(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.renders++
+	m.renderSeconds += d.Seconds()
+}
+
+func (m *serveMetrics) observeCache(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.cacheHits++
+	} else {
+		m.cacheMisses++
+	}
+}
+
+func (m *serveMetrics) write(w io.Writer, fonts, cached int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].path != keys[j].path {
+			return keys[i].path < keys[j].path
+		}
+		return keys[i].code < keys[j].code
+	})
+
+	fmt.Fprintln(w, "# HELP fontlet_http_requests_total HTTP requests by path and status code.")
+	fmt.Fprintln(w, "# TYPE fontlet_http_requests_total counter")
+	for _, k := range keys {
+		fmt.Fprintf(w, "fontlet_http_requests_total{path=%q,code=\"%d\"} %d\n", k.path, k.code, m.requests[k])
+	}
+	fmt.Fprintln(w, "# HELP fontlet_render_duration_seconds Time spent running figlet.")
+	fmt.Fprintln(w, "# TYPE fontlet_render_duration_seconds summary")
+	fmt.Fprintf(w, "fontlet_render_duration_seconds_sum %g\n", m.renderSeconds)
+	fmt.Fprintf(w, "fontlet_render_duration_seconds_count %d\n", m.renders)
+	fmt.Fprintln(w, "# HELP fontlet_render_cache_lookups_total Render cache lookups by result.")
+	fmt.Fprintln(w, "# TYPE fontlet_render_cache_lookups_total counter")
+	fmt.Fprintf(w, "fontlet_render_cache_lookups_total{result=\"hit\"} %d\n", m.cacheHits)
+	fmt.Fprintf(w, "fontlet_render_cache_lookups_total{result=\"miss\"} %d\n", m.cacheMisses)
+	fmt.Fprintln(w, "# HELP fontlet_render_cache_entries Renders cached in memory.")
+	fmt.Fprintln(w, "# TYPE fontlet_render_cache_entries gauge")
+	fmt.Fprintf(w, "fontlet_render_cache_entries %d\n", cached)
+	fmt.Fprintln(w, "# HELP fontlet_fonts Fonts available to render.")
+	fmt.Fprintln(w, "# TYPE fontlet_fonts gauge")
+	fmt.Fprintf(w, "fontlet_fonts %d\n", fonts)
+	fmt.Fprintln(w, "# HELP fontlet_uptime_seconds Seconds since the server started.")
+	fmt.Fprintln(w, "# TYPE fontlet_uptime_seconds gauge")
+	fmt.Fprintf(w, "fontlet_uptime_seconds %g\n", time.Since(m.started).Seconds())
+}
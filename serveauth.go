@@ -0,0 +1,60 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// --- Serve Authentication ---
+// With a token or basic-auth credentials configured, every endpoint but
+// /healthz (left open for liveness probes) needs them. A token is accepted
+// as `Authorization: Bearer TOKEN` or as the password of basic auth with any
+// user name, so browsers can use the web UI through their login prompt.
+
+type serveAuth struct {
+	token    string
+	user     string // Basic auth, with password
+	password string
+}
+
+func (a serveAuth) enabled() bool {
+	return a.token != "" || a.user != ""
+}
+
+// equalSecret compares in constant time, hashing first so lengths don't leak either.
+func equalSecret(got, want string) bool {
+	g, w := sha256.Sum256([]byte(got)), sha256.Sum256([]byte(want))
+	return subtle.ConstantTimeCompare(g[:], w[:]) == 1
+}
+
+func (a serveAuth) authorized(r *http.Request) bool {
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return a.token != "" && equalSecret(bearer, a.token)
+	}
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	if a.token != "" && equalSecret(password, a.token) {
+		return true
+	}
+	// Both compared every time, so a right user name doesn't answer faster
+	userOK, passwordOK := equalSecret(user, a.user), equalSecret(password, a.password)
+	return a.user != "" && userOK && passwordOK
+}
+
+func (a serveAuth) require(next http.Handler) http.Handler {
+	if !a.enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" && !a.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="fontlet", charset="UTF-8"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
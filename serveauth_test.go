@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeAuth(t *testing.T) {
+	token := serveAuth{token: "s3cret"}
+	basic := serveAuth{user: "ann", password: "pw"}
+	both := serveAuth{token: "s3cret", user: "ann", password: "pw"}
+	tests := []struct {
+		name     string
+		auth     serveAuth
+		path     string
+		header   string // Authorization, "" for none
+		user, pw string // Basic auth when user != ""
+		want     int
+	}{
+		{"off", serveAuth{}, "/", "", "", "", http.StatusOK},
+		{"no credentials", token, "/", "", "", "", http.StatusUnauthorized},
+		{"healthz open", token, "/healthz", "", "", "", http.StatusOK},
+		{"bearer", token, "/render", "Bearer s3cret", "", "", http.StatusOK},
+		{"wrong bearer", token, "/render", "Bearer s3cre", "", "", http.StatusUnauthorized},
+		{"bearer prefix only", token, "/render", "Bearer ", "", "", http.StatusUnauthorized},
+		{"bearer without token", basic, "/render", "Bearer pw", "", "", http.StatusUnauthorized},
+		{"token as password", token, "/", "", "anyone", "s3cret", http.StatusOK},
+		{"basic", basic, "/", "", "ann", "pw", http.StatusOK},
+		{"wrong password", basic, "/", "", "ann", "px", http.StatusUnauthorized},
+		{"wrong user", basic, "/", "", "bob", "pw", http.StatusUnauthorized},
+		{"empty password", basic, "/", "", "ann", "", http.StatusUnauthorized},
+		{"token with user set", both, "/", "Bearer s3cret", "", "", http.StatusOK},
+		{"basic with token set", both, "/", "", "ann", "pw", http.StatusOK},
+		{"empty basic without user", token, "/", "", "x", "", http.StatusUnauthorized},
+		{"malformed header", token, "/", "Token s3cret", "", "", http.StatusUnauthorized},
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		if tt.user != "" {
+			r.SetBasicAuth(tt.user, tt.pw)
+		}
+		w := httptest.NewRecorder()
+		tt.auth.require(ok).ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.want)
+		}
+		if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: 401 without WWW-Authenticate", tt.name)
+		}
+	}
+}
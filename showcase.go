@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Showcase Mode ---
+// One font at a time at full terminal width, for judging intricate fonts that
+// list previews truncate. Moving through fonts moves the list selection, so
+// leaving the showcase keeps the position (and any filter).
+
+type showcaseRenderedMsg struct {
+	path, output string
+}
+
+func (m model) showcaseWidth() int {
+	return max(m.termWidth-docStyle.GetHorizontalFrameSize(), 20)
+}
+
+// showcaseStep moves the list selection by delta, wrapping around, and renders
+// the new font if it hasn't been yet.
+func (m model) showcaseStep(delta int) (model, tea.Cmd) {
+	n := len(m.fontList.VisibleItems())
+	if n == 0 {
+		return m, nil
+	}
+	m.fontList.Select(((m.fontList.Index()+delta)%n + n) % n)
+	return m, m.ensureShowcaseRender()
+}
+
+// ensureShowcaseRender renders the selected font unless a render is cached.
+func (m model) ensureShowcaseRender() tea.Cmd {
+	font, ok := m.highlightedFont()
+	if !ok {
+		return nil
+	}
+	if _, done := m.showcaseRenders[font.Path]; done {
+		return nil
+	}
+	width := m.showcaseWidth()
+	return func() tea.Msg {
+		output, err := runFiglet(m.figletCmdPath, font.Path, m.renderText(), width)
+		if err != nil {
+			output = fmt.Sprintf("Error rendering: %v", err)
+		}
+		return showcaseRenderedMsg{path: font.Path, output: output}
+	}
+}
+
+// showcaseView shows the selected font's render, clipped to the given height.
+func (m model) showcaseView(height int) string {
+	font, ok := m.highlightedFont()
+	if !ok {
+		return statusMessageStyle.Render("No fonts match the filter.")
+	}
+	position := fmt.Sprintf("%d/%d", m.fontList.Index()+1, len(m.fontList.VisibleItems()))
+	header := fontNameStyle.Render(font.Name) + "  " + sourceStyle.Render(position+"  "+font.sourceAnnotation()+m.usageAnnotation(font))
+
+	body, rendered := m.showcaseRenders[font.Path]
+	if !rendered {
+		body = "Rendering..."
+	}
+	lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+	if len(lines) > height-2 {
+		lines = lines[:max(height-2, 0)]
+	}
+	pane := renderer.NewStyle().MaxWidth(m.showcaseWidth())
+	return pane.Render(header + "\n\n" + figletOutputStyle.Render(strings.Join(lines, "\n")))
+}
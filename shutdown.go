@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Shutdown ---
+// Quitting while previews render must not leave figlet processes or temp
+// files behind. Every figlet runs in a process group of its own, under
+// shutdownCtx: shutdown cancels it, which kills the groups, waits a moment
+// for the processes to be reaped and removes temp files that were never
+// renamed into place. The TUI calls it once Bubble Tea returns, which
+// happens on ctrl+c, SIGINT and SIGTERM alike; SIGHUP, which Bubble Tea
+// leaves alone, is turned into a kill first.
+
+var shutdownCtx, cancelChildren = context.WithCancel(context.Background())
+
+var (
+	runningChildren atomic.Int64 // Child processes started and not yet reaped
+	tempFiles       sync.Map     // Paths of temp files not yet renamed into place
+)
+
+// runChild runs cmd like cmd.Output, in its own process group, counted in
+// runningChildren. cmd must come from exec.CommandContext.
+func runChild(cmd *exec.Cmd) ([]byte, error) {
+	ownProcessGroup(cmd)
+	runningChildren.Add(1)
+	defer runningChildren.Add(-1)
+	return cmd.Output()
+}
+
+// shutdown kills child processes, waits up to a second for them to be
+// reaped, and removes leftover temp files. Calling it again is harmless.
+func shutdown() {
+	cancelChildren()
+	for deadline := time.Now().Add(time.Second); runningChildren.Load() > 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	tempFiles.Range(func(path, _ any) bool {
+		os.Remove(path.(string))
+		tempFiles.Delete(path)
+		return true
+	})
+}
+
+// killOnHangup ends p when the terminal goes away, so its caller gets to
+// shut down. The returned func stops watching.
+func killOnHangup(p *tea.Program) func() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-hup:
+			p.Kill()
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(hup)
+		close(done)
+	}
+}
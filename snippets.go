@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Snippets ---
+// Named texts rendered often (project names, "WARNING", slogans). They're
+// stored in the config's "snippets" array and picked from stateInputText.
+
+type snippet struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+// For list.Item interface
+func (s snippet) Title() string       { return s.Name }
+func (s snippet) Description() string { return s.Text }
+func (s snippet) FilterValue() string { return s.Name + " " + s.Text }
+
+// newSnippetList builds the picker for the configured snippets.
+func (m model) newSnippetList() list.Model {
+	items := make([]list.Item, len(m.cfg.Snippets))
+	for i, sn := range m.cfg.Snippets {
+		items[i] = sn
+	}
+	listHeight := m.termHeight - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView()) - 2
+	l := list.New(items, list.NewDefaultDelegate(), m.termWidth-docStyle.GetHorizontalFrameSize(), listHeight)
+	l.Title = "Snippets"
+	l.Styles.Title = listTitleStyle
+	l.Styles.HelpStyle = helpStyle.MarginTop(0)
+	l.Styles.StatusBar = statusMessageStyle.Padding(0, 1)
+	l.SetStatusBarItemName("snippet", "snippets")
+	l.SetShowHelp(false) // The footer lists the picker's own keys
+	return l
+}
+
+// withSnippets replaces the snippet list in the config and persists it.
+func (m model) withSnippets(snippets []snippet) (model, error) {
+	cfg := m.cfg
+	cfg.Snippets = snippets
+	if snippets == nil {
+		snippets = []snippet{} // An empty list rather than null in the file
+	}
+	if err := saveConfigKey("snippets", snippets); err != nil {
+		return m, err
+	}
+	m.cfg = cfg
+	m.configModTime = configModTime() // Our own write, not an external edit to reload
+	return m, nil
+}
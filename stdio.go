@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+)
+
+// --- Stdio Protocol ---
+// `fontlet --stdio` serves editor plugins over stdin/stdout, one JSON object
+// per line each way. Every request gets exactly one response, in order, with
+// the request's id echoed back:
+//
+//	{"id": 1, "method": "list-fonts"}
+//	{"id": 2, "method": "render", "font": "slant", "text": "Hi", "width": 60, "filter": "braille"}
+//	{"id": 3, "method": "preview", "font": "slant", "text": "Hi", "lines": 4}
+//
+// Failures are reported in the response's "error" field, including lines
+// over maxStdioRequest; the server keeps running until stdin is closed.
+
+type stdioRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Font   string          `json:"font,omitempty"` // Font name, or "random"
+	Text   string          `json:"text,omitempty"`
+	Width  int             `json:"width,omitempty"`  // Default 80
+	Filter string          `json:"filter,omitempty"` // Output filter name, render only
+	Lines  int             `json:"lines,omitempty"`  // Preview height, default as in the font list
+}
+
+type stdioResponse struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Output string          `json:"output,omitempty"`
+	Fonts  []stdioFont     `json:"fonts,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type stdioFont struct {
+	Name   string    `json:"name"`
+	Path   string    `json:"path"`
+	Dir    string    `json:"dir"`
+	Header flfHeader `json:"header"`
+}
+
+// maxStdioRequest bounds a request line; texts are short, this is generous.
+const maxStdioRequest = 1 << 20
+
+func runStdio(stdin io.Reader, stdout io.Writer) error {
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	rng := newRand(globalOptions.seed, globalOptions.seeded)
+	enc := json.NewEncoder(stdout)
+	enc.SetEscapeHTML(false)
+
+	br := bufio.NewReaderSize(stdin, 64<<10)
+	for {
+		line, err := readStdioLine(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil && err != bufio.ErrTooLong {
+			return err
+		}
+		if err == nil && len(line) == 0 {
+			continue
+		}
+		var req stdioRequest
+		resp := stdioResponse{}
+		if err == bufio.ErrTooLong {
+			resp.Error = fmt.Sprintf("invalid request: over %d bytes", maxStdioRequest)
+		} else if err := json.Unmarshal(line, &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			resp = env.handleStdio(req, rng)
+			resp.ID = req.ID
+		}
+		if err := enc.Encode(resp); err != nil { // Encode writes the line's newline
+			return err
+		}
+	}
+}
+
+// readStdioLine reads a line without its line ending. A line over
+// maxStdioRequest is read to its end and dropped, with bufio.ErrTooLong, so
+// the next request is read from the start of its line. A last line without
+// a newline still counts; io.EOF comes once nothing is left.
+func readStdioLine(br *bufio.Reader) ([]byte, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if !tooLong && len(line)+len(chunk) > maxStdioRequest+2 { // Room for "\r\n"
+			line, tooLong = nil, true
+		}
+		if !tooLong {
+			line = append(line, chunk...)
+		}
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && (len(line) > 0 || tooLong):
+		case err != nil:
+			return nil, err
+		}
+		if tooLong {
+			return nil, bufio.ErrTooLong
+		}
+		return bytes.TrimRight(line, "\r\n"), nil
+	}
+}
+
+func (env cliEnv) handleStdio(req stdioRequest, rng *rand.Rand) stdioResponse {
+	fail := func(format string, args ...any) stdioResponse {
+		return stdioResponse{Error: fmt.Sprintf(format, args...)}
+	}
+	switch req.Method {
+	case "list-fonts":
+		fonts := make([]stdioFont, len(env.fonts))
+		for i, f := range env.fonts {
+			fonts[i] = stdioFont{Name: f.Name, Path: f.Path, Dir: f.Dir, Header: f.Header}
+		}
+		return stdioResponse{Fonts: fonts}
+	case "render", "preview":
+		if req.Font == "" || req.Text == "" {
+			return fail("%s needs a font and text", req.Method)
+		}
+		font, err := pickFont(env.fonts, req.Font, rng)
+		if err != nil {
+			return fail("%v", err)
+		}
+		width := req.Width
+		if width <= 0 {
+			width = 80
+		}
+		output, err := runFiglet(env.figletCmdPath, font.Path, req.Text, width)
+		if err != nil {
+			return fail("%v", err)
+		}
+		if req.Method == "preview" {
+			lines := req.Lines
+			if lines <= 0 {
+				lines = previewLines
+			}
+			return stdioResponse{Output: truncateString(output, lines)}
+		}
+		if req.Filter == "" {
+			return stdioResponse{Output: output}
+		}
+		for _, f := range outputFilters {
+			if f.Name == req.Filter {
+				return stdioResponse{Output: f.Apply(output)}
+			}
+		}
+		return fail("unknown filter %q", req.Filter)
+	case "":
+		return fail("missing method")
+	}
+	return fail("unknown method %q, expected render, preview or list-fonts", req.Method)
+}
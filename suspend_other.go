@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// forwardSuspendSignals does nothing where there's no job control.
+func forwardSuspendSignals(*tea.Program) {}
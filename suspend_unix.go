@@ -0,0 +1,33 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// forwardSuspendSignals makes a SIGTSTP from outside (kill -TSTP, a job
+// control shell) suspend like ctrl+z does, releasing the terminal first;
+// the default action would stop fontlet with the terminal still in raw mode
+// and the alt screen up.
+func forwardSuspendSignals(p *tea.Program) {
+	tstp := make(chan os.Signal, 1)
+	cont := make(chan os.Signal, 1)
+	signal.Notify(tstp, syscall.SIGTSTP)
+	signal.Notify(cont, syscall.SIGCONT)
+	go func() {
+		for range tstp {
+			signal.Stop(tstp) // The SIGTSTP Bubble Tea raises once the terminal is released must really stop us
+			for len(cont) > 0 {
+				<-cont // Drop continues from before this suspend
+			}
+			p.Send(tea.SuspendMsg{})
+			<-cont
+			signal.Notify(tstp, syscall.SIGTSTP)
+		}
+	}()
+}
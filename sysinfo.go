@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Sysinfo Command ---
+// `fontlet sysinfo` is a tiny neofetch for shell startup files: each line of
+// a template (hostname, uptime, load, IP address...) becomes a figlet block,
+// stacked top to bottom. With --image the blocks are images (see
+// inlineimage.go). Values the system can't tell, like the uptime in a
+// container, the IP address offline or the git branch outside a repository,
+// show as "?" rather than fail the command in the middle of a shell's startup.
+
+const defaultSysinfoTemplate = "{hostname}\nup {uptime}\nload {load}\n{ip}"
+
+func sysinfoVars() []templateVar {
+	return []templateVar{
+		{Name: "hostname", Desc: "host name", Value: os.Hostname},
+		{Name: "user", Desc: "user name", Value: func() (string, error) {
+			u, err := user.Current()
+			if err != nil {
+				return "", err
+			}
+			return u.Username, nil
+		}},
+		{Name: "os", Desc: "operating system", Value: func() (string, error) { return runtime.GOOS, nil }},
+		{Name: "uptime", Desc: "time since boot, e.g. 3d 4h", Value: func() (string, error) {
+			up, err := uptime()
+			if err != nil {
+				return "", err
+			}
+			return formatUptime(up), nil
+		}},
+		{Name: "load", Desc: "1 minute load average", Value: func() (string, error) { return loadAverage(0) }},
+		{Name: "load5", Desc: "5 minute load average", Value: func() (string, error) { return loadAverage(1) }},
+		{Name: "load15", Desc: "15 minute load average", Value: func() (string, error) { return loadAverage(2) }},
+		{Name: "ip", Desc: "first non-loopback IPv4 address", Value: primaryIP},
+	}
+}
+
+func runSysinfo(args []string, stdout io.Writer) error {
+	vars := orUnknown(append(sysinfoVars(), gitVars()...))
+	fs := newFlagSet("sysinfo")
+	tmpl := fs.String("template", defaultSysinfoTemplate, `lines to render, each its own block ("\n" separates lines); placeholders: `+placeholderHelp(vars))
+	fontName := fs.String("font", "small", `font of the blocks ("random" for a random one)`)
+	width := fs.Int("width", terminalWidth(), "output width in columns")
+	image := fs.Bool("image", false, imageFlagUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("sysinfo takes no arguments")
+	}
+	protocol, err := imageFlag(*image)
+	if err != nil {
+		return err
+	}
+
+	text, err := expandTemplate(strings.ReplaceAll(*tmpl, `\n`, "\n"), vars)
+	if err != nil {
+		return err
+	}
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	font, err := pickFontFuzzy(env.fonts, *fontName, newRand(globalOptions.seed, globalOptions.seeded))
+	if err != nil {
+		return err
+	}
+	return writeStackedBlocks(stdout, env, font, text, *width, protocol)
+}
+
+// orUnknown makes vars show "?" for values that are unavailable.
+func orUnknown(vars []templateVar) []templateVar {
+	wrapped := make([]templateVar, len(vars))
+	for i, v := range vars {
+		value := v.Value
+		v.Value = func() (string, error) {
+			if s, err := value(); err == nil {
+				return s, nil
+			}
+			return "?", nil
+		}
+		wrapped[i] = v
+	}
+	return wrapped
+}
+
+// writeStackedBlocks renders every non-blank line of text as its own block,
+// drawn as an image unless image is imageNone.
+func writeStackedBlocks(stdout io.Writer, env cliEnv, font fontMetadata, text string, width int, image imageProtocol) error {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		output, err := runFiglet(env.figletCmdPath, font.Path, line, width)
+		if err != nil {
+			return err
+		}
+		banner := trimBanner(output)
+		block := figletOutputStyle.Render(banner) + "\n"
+		if image != imageNone {
+			data, err := bannerPNG(banner, bannerRGB(env.cfg.Theme))
+			if err != nil {
+				return err
+			}
+			rows := len(bannerLines(banner))
+			block = image.inline(data, 0, lipgloss.Width(banner), rows) + image.lineAfter(rows)
+		}
+		if _, err := io.WriteString(stdout, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uptime reads /proc/uptime on Linux and asks sysctl for the boot time on
+// the BSDs and macOS.
+func uptime() (time.Duration, error) {
+	if data, err := os.ReadFile("/proc/uptime"); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) > 0 {
+			secs, err := strconv.ParseFloat(fields[0], 64)
+			if err == nil {
+				return time.Duration(secs * float64(time.Second)), nil
+			}
+		}
+	}
+	out, err := exec.Command("sysctl", "-n", "kern.boottime").Output() // "{ sec = 1700000000, usec = 0 } ..."
+	if err != nil {
+		return 0, errors.New("uptime is unavailable on this system")
+	}
+	var sec int64
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(out)), "{ sec = %d,", &sec); err != nil {
+		return 0, fmt.Errorf("unexpected kern.boottime %q", strings.TrimSpace(string(out)))
+	}
+	return time.Since(time.Unix(sec, 0)), nil
+}
+
+// formatUptime keeps the two largest units, e.g. "3d 4h" or "12m".
+func formatUptime(d time.Duration) string {
+	days, hours, mins := int(d.Hours())/24, int(d.Hours())%24, int(d.Minutes())%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
+
+// loadAverage returns the 1, 5 or 15 minute load average (i = 0, 1, 2).
+func loadAverage(i int) (string, error) {
+	var fields []string
+	if data, err := os.ReadFile("/proc/loadavg"); err == nil {
+		fields = strings.Fields(string(data))
+	} else if out, err := exec.Command("sysctl", "-n", "vm.loadavg").Output(); err == nil { // "{ 1.23 1.10 0.98 }"
+		fields = strings.Fields(strings.Trim(strings.TrimSpace(string(out)), "{}"))
+	}
+	if len(fields) <= i {
+		return "", errors.New("load average is unavailable on this system")
+	}
+	return fields[i], nil
+}
+
+// primaryIP is the first IPv4 address of an interface that is up and not a loopback.
+func primaryIP() (string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				return ipNet.IP.String(), nil
+			}
+		}
+	}
+	return "", errors.New("no network address found")
+}
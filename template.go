@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- Text Templates ---
+// Commands that render generated text (sysinfo, ...) take a template with
+// {name} placeholders. Values are only looked up when the template uses them.
+
+type templateVar struct {
+	Name  string
+	Desc  string
+	Value func() (string, error)
+}
+
+var placeholderPattern = regexp.MustCompile(`\{([a-z0-9_]+)\}`)
+
+// expandTemplate fills in the placeholders of tmpl from vars.
+func expandTemplate(tmpl string, vars []templateVar) (string, error) {
+	byName := make(map[string]templateVar, len(vars))
+	for _, v := range vars {
+		byName[v.Name] = v
+	}
+	values := make(map[string]string)
+	var err error
+	expanded := placeholderPattern.ReplaceAllStringFunc(tmpl, func(p string) string {
+		name := p[1 : len(p)-1]
+		if value, ok := values[name]; ok || err != nil {
+			return value
+		}
+		v, ok := byName[name]
+		if !ok {
+			err = fmt.Errorf("unknown placeholder %s, expected one of %s", p, placeholderNames(vars))
+			return ""
+		}
+		value, verr := v.Value()
+		if verr != nil {
+			err = fmt.Errorf("%s: %w", p, verr)
+			return ""
+		}
+		values[name] = value
+		return value
+	})
+	return expanded, err
+}
+
+// placeholderNames lists the placeholders of vars, e.g. "{hostname}, {ip}".
+func placeholderNames(vars []templateVar) string {
+	names := make([]string, len(vars))
+	for i, v := range vars {
+		names[i] = "{" + v.Name + "}"
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// placeholderHelp documents vars for a flag's usage text.
+func placeholderHelp(vars []templateVar) string {
+	parts := make([]string, len(vars))
+	for i, v := range vars {
+		parts[i] = fmt.Sprintf("{%s} %s", v.Name, v.Desc)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// --- Text Transforms ---
+// Novelty spellings applied to the text before rendering: leetspeak, small
+// caps and upside down. F3 on the text screen opens the menu, each entry
+// showing the text as it would become. The text itself is kept as typed, so
+// going back to edit it or picking another transform doesn't stack them.
+// Small caps and upside-down letters are Unicode, drawn only by fonts that
+// have glyphs for them.
+
+type textTransform struct {
+	Name  string
+	Desc  string
+	Apply func(string) string
+}
+
+var textTransforms = []textTransform{
+	{Name: "none", Desc: "The text as typed", Apply: func(s string) string { return s }},
+	{Name: "leet", Desc: "Leetspeak: digits for letters, in any font", Apply: leetspeak},
+	{Name: "small caps", Desc: "Unicode small capitals, for fonts that draw them", Apply: smallCaps},
+	{Name: "upside down", Desc: "Flipped and reversed, for fonts that draw the flipped letters", Apply: upsideDown},
+}
+
+// renderText is the text to render: the input with the transform applied.
+func (m model) renderText() string {
+	return textTransforms[m.transformIndex].Apply(m.inputText)
+}
+
+var leetMap = strings.NewReplacer(
+	"a", "4", "A", "4", "e", "3", "E", "3", "i", "1", "I", "1",
+	"o", "0", "O", "0", "s", "5", "S", "5", "t", "7", "T", "7",
+)
+
+func leetspeak(s string) string { return leetMap.Replace(s) }
+
+var smallCapsMap = strings.NewReplacer(
+	"a", "ᴀ", "b", "ʙ", "c", "ᴄ", "d", "ᴅ", "e", "ᴇ", "f", "ꜰ", "g", "ɢ",
+	"h", "ʜ", "i", "ɪ", "j", "ᴊ", "k", "ᴋ", "l", "ʟ", "m", "ᴍ", "n", "ɴ",
+	"o", "ᴏ", "p", "ᴘ", "q", "ǫ", "r", "ʀ", "s", "ꜱ", "t", "ᴛ", "u", "ᴜ",
+	"v", "ᴠ", "w", "ᴡ", "y", "ʏ", "z", "ᴢ",
+)
+
+// smallCaps keeps capitals, as small caps set them, and has no small x.
+func smallCaps(s string) string { return smallCapsMap.Replace(s) }
+
+var flipped = map[rune]rune{
+	'a': 'ɐ', 'b': 'q', 'c': 'ɔ', 'd': 'p', 'e': 'ǝ', 'f': 'ɟ', 'g': 'ƃ', 'h': 'ɥ',
+	'i': 'ᴉ', 'j': 'ɾ', 'k': 'ʞ', 'm': 'ɯ', 'n': 'u', 'p': 'd', 'q': 'b', 'r': 'ɹ',
+	't': 'ʇ', 'u': 'n', 'v': 'ʌ', 'w': 'ʍ', 'y': 'ʎ',
+	'A': '∀', 'C': 'Ɔ', 'E': 'Ǝ', 'F': 'Ⅎ', 'G': '⅁', 'J': 'ſ', 'L': '˥', 'M': 'W',
+	'P': 'Ԁ', 'T': '┴', 'U': '∩', 'V': 'Λ', 'W': 'M', 'Y': '⅄',
+	'1': 'Ɩ', '2': 'ᄅ', '3': 'Ɛ', '4': 'ㄣ', '5': 'ϛ', '6': '9', '7': 'ㄥ', '9': '6',
+	'.': '˙', ',': '\'', '\'': ',', '"': '„', '!': '¡', '?': '¿', '&': '⅋', '_': '‾',
+	'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<',
+}
+
+// upsideDown reads right when the banner is turned around: reversed, with
+// each character flipped. Symmetric ones like o, s, x and z stay.
+func upsideDown(s string) string {
+	runes := []rune(s)
+	slices.Reverse(runes)
+	for i, r := range runes {
+		if f, ok := flipped[r]; ok {
+			runes[i] = f
+		}
+	}
+	return string(runes)
+}
+
+// transformItem is a transform in the menu, with its sample of the text.
+type transformItem struct {
+	textTransform
+	sample string
+}
+
+// For list.Item interface
+func (t transformItem) Title() string       { return t.Name }
+func (t transformItem) Description() string { return t.sample }
+func (t transformItem) FilterValue() string { return t.Name }
+
+// newTransformMenu lists the transforms with text transformed by each.
+func (m model) newTransformMenu(text string) list.Model {
+	items := make([]list.Item, len(textTransforms))
+	for i, t := range textTransforms {
+		sample := t.Desc
+		if text != "" {
+			sample = fmt.Sprintf("%s — %s", t.Apply(text), t.Desc)
+		}
+		items[i] = transformItem{t, sample}
+	}
+	listHeight := m.termHeight - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView()) - 2
+	l := list.New(items, list.NewDefaultDelegate(), m.termWidth-docStyle.GetHorizontalFrameSize(), listHeight)
+	l.Title = "Transforms"
+	l.Styles.Title = listTitleStyle
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false) // The footer lists the menu's keys
+	l.Select(m.transformIndex)
+	return l
+}
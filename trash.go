@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// --- Font Trash ---
+// `fontlet fonts remove NAME` moves a font from one of the configured font
+// dirs into a trash dir next to the history, and hides fonts it can't or
+// shouldn't delete (system fonts) from discovery instead. Both are recorded
+// in trash.json, so `fontlet fonts restore NAME` undoes either.
+
+type trashEntry struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`              // Where the font was found
+	Trashed string    `json:"trashed,omitempty"` // The file in the trash dir, empty for hidden fonts
+	Time    time.Time `json:"time"`
+}
+
+func trashDir() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trash"), nil
+}
+
+func loadTrash() ([]trashEntry, error) {
+	dir, err := trashDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "trash.json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font trash %s: %w", path, err)
+	}
+	var entries []trashEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid font trash %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+func saveTrash(entries []trashEntry) error {
+	dir, err := trashDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(filepath.Join(dir, "trash.json"), 0644, func(f *os.File) error {
+		_, err := f.Write(append(data, '\n'))
+		return err
+	})
+}
+
+// hiddenFonts are the paths discovery skips. An unreadable trash hides nothing.
+func hiddenFonts() map[string]bool {
+	entries, _ := loadTrash()
+	hidden := make(map[string]bool)
+	for _, e := range entries {
+		if e.Trashed == "" {
+			hidden[e.Path] = true
+		}
+	}
+	return hidden
+}
+
+// isUserFont reports whether path is inside one of the configured font dirs
+// or the imported fonts dir, where fontlet may delete files.
+func isUserFont(cfg config, path string) bool {
+	dirs := cfg.FontDirs
+	if dir, err := importedFontsDir(); err == nil {
+		dirs = append(dirs[:len(dirs):len(dirs)], dir)
+	}
+	for _, dir := range dirs {
+		if rel, err := filepath.Rel(expandHome(dir), path); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// moveFile renames, falling back to copy and delete across filesystems.
+func moveFile(from, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if err := writeAtomic(to, 0644, func(f *os.File) error { // Never half a font at either end
+		_, err := f.Write(data)
+		return err
+	}); err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
+
+func runFontsRemove(args []string, stdout io.Writer) error {
+	fs := newFlagSet("fonts")
+	hide := fs.Bool("hide", false, "hide the font even if it's in a configured font dir, leaving the file alone")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("fonts remove needs exactly one font name")
+	}
+	env, err := loadCLIEnv()
+	if err != nil {
+		return err
+	}
+	if fs.Arg(0) == randomFontName {
+		return fmt.Errorf("fonts remove needs a font name, not %q", randomFontName)
+	}
+	font, err := pickFont(env.fonts, fs.Arg(0), nil)
+	if err != nil {
+		return err
+	}
+	entries, err := loadTrash()
+	if err != nil {
+		return err
+	}
+	entry := trashEntry{Name: font.Name, Path: font.Path, Time: time.Now()}
+	if !*hide && isUserFont(env.cfg, font.Path) {
+		dir, err := trashDir()
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		entry.Trashed = filepath.Join(dir, fmt.Sprintf("%d-%s", entry.Time.UnixNano(), filepath.Base(font.Path)))
+		if err := moveFile(font.Path, entry.Trashed); err != nil {
+			return fmt.Errorf("failed to move %s to the trash: %w", font.Path, err)
+		}
+	}
+	if err := saveTrash(append(entries, entry)); err != nil {
+		return err
+	}
+	if entry.Trashed != "" {
+		fmt.Fprintf(stdout, "Moved %s to the trash (fontlet fonts restore %s to undo)\n", shortenHome(font.Path), font.Name)
+	} else {
+		fmt.Fprintf(stdout, "Hid %s (fontlet fonts restore %s to undo)\n", shortenHome(font.Path), font.Name)
+	}
+	if len(font.Shadows) > 0 {
+		fmt.Fprintf(stdout, "%s now refers to %s\n", font.Name, shortenHome(font.Shadows[0]))
+	}
+	return nil
+}
+
+func runFontsRestore(args []string, stdout io.Writer) error {
+	fs := newFlagSet("fonts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("fonts restore needs exactly one font name")
+	}
+	entries, err := loadTrash()
+	if err != nil {
+		return err
+	}
+	for i := len(entries) - 1; i >= 0; i-- { // The most recent removal of that name
+		e := entries[i]
+		if e.Name != fs.Arg(0) {
+			continue
+		}
+		if e.Trashed != "" {
+			if _, err := os.Stat(e.Path); err == nil {
+				return fmt.Errorf("%s exists again, move it away first", e.Path)
+			}
+			if err := os.MkdirAll(filepath.Dir(e.Path), 0755); err != nil {
+				return err
+			}
+			if err := moveFile(e.Trashed, e.Path); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", e.Path, err)
+			}
+		}
+		if err := saveTrash(append(entries[:i:i], entries[i+1:]...)); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Restored %s\n", shortenHome(e.Path))
+		return nil
+	}
+	return fmt.Errorf("no removed font named %q (see fontlet fonts trash)", fs.Arg(0))
+}
+
+func runFontsTrash(args []string, stdout io.Writer) error {
+	fs := newFlagSet("fonts")
+	empty := fs.Bool("empty", false, "delete the trashed font files for good; hidden fonts stay hidden")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	entries, err := loadTrash()
+	if err != nil {
+		return err
+	}
+	if *empty {
+		var kept []trashEntry
+		deleted := 0
+		for _, e := range entries {
+			if e.Trashed == "" {
+				kept = append(kept, e)
+				continue
+			}
+			if err := os.Remove(e.Trashed); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			deleted++
+		}
+		if err := saveTrash(kept); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Deleted %d trashed fonts\n", deleted)
+		return nil
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(stdout, "The font trash is empty")
+		return nil
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	for _, e := range entries {
+		state := "trashed"
+		if e.Trashed == "" {
+			state = "hidden"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Name, state, e.Time.Local().Format("2006-01-02 15:04"), shortenHome(e.Path))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Usage Statistics ---
+// How often each font was rendered in full and saved, kept next to the
+// history. The counts show in the showcase and highlight panes, and the font
+// list can be sorted by them, which helps to find the fonts worth keeping
+// when pruning a big collection.
+
+type fontUsage struct {
+	Renders  int       `json:"renders"`
+	Exports  int       `json:"exports"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+type usageStats map[string]fontUsage // Font path -> usage
+
+type usageSavedMsg struct{ err error }
+
+func usagePath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.json"), nil
+}
+
+// loadUsage returns the saved counts. A missing file means nothing was used yet.
+func loadUsage() (usageStats, error) {
+	stats := usageStats{}
+	path, err := usagePath()
+	if err != nil {
+		return stats, nil // No home directory or --no-state, count this session only
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, fmt.Errorf("failed to read usage statistics %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return usageStats{}, fmt.Errorf("invalid usage statistics %s: %w", path, err)
+	}
+	return stats, nil
+}
+
+func saveUsage(stats usageStats) error {
+	path, err := usagePath()
+	if errors.Is(err, errNoState) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeAtomic(path, 0644, func(f *os.File) error {
+		_, err := f.Write(append(data, '\n'))
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to write usage statistics %s: %w", path, err)
+	}
+	return nil
+}
+
+// recordUsage counts a full render or a save of the selected font.
+func (m *model) recordUsage(export bool) tea.Cmd {
+	u := m.usage[m.selectedFontMeta.Path]
+	if export {
+		u.Exports++
+	} else {
+		u.Renders++
+	}
+	u.LastUsed = time.Now()
+	m.usage[m.selectedFontMeta.Path] = u
+	snapshot := make(usageStats, len(m.usage))
+	for path, u := range m.usage {
+		snapshot[path] = u
+	}
+	return func() tea.Msg { return usageSavedMsg{saveUsage(snapshot)} }
+}
+
+// usageAnnotation describes a font's usage for pane headers, empty if it was never used.
+func (m model) usageAnnotation(font fontMetadata) string {
+	u, ok := m.usage[font.Path]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("  rendered %d×, saved %d×", u.Renders, u.Exports)
+}
+
+// fontListItems are the fonts in list order: by name (see fontsort.go), or
+// most used first (renders and saves together, most recent first on ties).
+func (m model) fontListItems() []list.Item {
+	fonts := append([]fontMetadata(nil), m.fonts...)
+	sortFontNames(fonts, m.cfg.FontSort)
+	if m.sortByUsage {
+		sort.SliceStable(fonts, func(i, j int) bool {
+			a, b := m.usage[fonts[i].Path], m.usage[fonts[j].Path]
+			if a.Renders+a.Exports != b.Renders+b.Exports {
+				return a.Renders+a.Exports > b.Renders+b.Exports
+			}
+			return a.LastUsed.After(b.LastUsed)
+		})
+	}
+	items := make([]list.Item, len(fonts))
+	for i, f := range fonts {
+		items[i] = f
+	}
+	return items
+}
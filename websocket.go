@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- WebSocket ---
+// Just enough of RFC 6455 for the live preview: the server side of the
+// handshake, (possibly fragmented) text messages from the browser, pings and
+// close. Replies are single unmasked frames; no extensions are negotiated.
+// Frames breaking the RFC's framing rules end the connection, as does a
+// client sending nothing, not even a pong, for wsReadTimeout.
+
+// maxWSMessage bounds an incoming message; preview requests are tiny.
+const maxWSMessage = 64 << 10
+
+// wsReadTimeout is how long a frame may take to arrive. The server pings
+// idle connections more often than that (see wsPingInterval), and browsers
+// answer pings on their own, so only clients that went away time out.
+// wsWriteTimeout bounds each write, so a client that stops reading can't
+// hold the writer (and the lock the reader needs for pongs) forever.
+const (
+	wsReadTimeout  = time.Minute
+	wsPingInterval = wsReadTimeout / 2
+	wsWriteTimeout = 10 * time.Second
+)
+
+// maxWSControl is the largest payload of a control frame (RFC 6455 5.5).
+const maxWSControl = 125
+
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+	wmu  sync.Mutex // Writes come from the reader (pongs) and the renderer
+}
+
+// upgradeWebSocket performs the handshake and takes over the connection.
+// Cross-origin upgrades are refused, so other sites open in the user's
+// browser can't drive a server on their network.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		return nil, errors.New("not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, errors.New("unsupported websocket version, expected 13")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !strings.EqualFold(u.Host, r.Host) {
+			return nil, fmt.Errorf("cross-origin websocket from %q refused", origin)
+		}
+	}
+
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum([]byte(key + wsGUID))
+	fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	if err := brw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, br: brw.Reader}, nil
+}
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readMessage returns the next text message, answering pings on the way.
+// It returns io.EOF once the client closes the connection.
+func (c *wsConn) readMessage() (string, error) {
+	var msg []byte
+	fragmented := false // A text frame without FIN started msg
+	for {
+		if err := c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout)); err != nil {
+			return "", err
+		}
+		var head [2]byte
+		if _, err := io.ReadFull(c.br, head[:]); err != nil {
+			return "", err
+		}
+		fin, opcode, masked := head[0]&0x80 != 0, head[0]&0x0F, head[1]&0x80 != 0
+		if !masked {
+			return "", c.protocolError("unmasked frame from client")
+		}
+		n := uint64(head[1] & 0x7F)
+		if opcode&0x8 != 0 && (!fin || n > maxWSControl) { // Control frames can't be fragmented or long
+			return "", c.protocolError("invalid websocket control frame")
+		}
+		switch n {
+		case 126:
+			var ext [2]byte
+			if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+				return "", err
+			}
+			n = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+				return "", err
+			}
+			n = binary.BigEndian.Uint64(ext[:])
+		}
+		if n > maxWSMessage || uint64(len(msg))+n > maxWSMessage {
+			c.writeFrame(wsClose, []byte{0x03, 0xF1}) // 1009: message too big
+			return "", fmt.Errorf("websocket message over %d bytes", maxWSMessage)
+		}
+		var mask [4]byte
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return "", err
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(c.br, payload); err != nil {
+			return "", err
+		}
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+
+		switch opcode {
+		case wsText, wsContinuation:
+			if (opcode == wsContinuation) != fragmented {
+				return "", c.protocolError("websocket continuation frame out of place")
+			}
+			msg = append(msg, payload...)
+			if fin {
+				return string(msg), nil
+			}
+			fragmented = true
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return "", err
+			}
+		case wsPong:
+		case wsClose:
+			c.writeFrame(wsClose, nil)
+			return "", io.EOF
+		case wsBinary:
+			return "", errors.New("binary websocket messages aren't supported")
+		default:
+			return "", fmt.Errorf("unknown websocket opcode %#x", opcode)
+		}
+	}
+}
+
+// protocolError closes the connection with status 1002 and returns msg as
+// the error.
+func (c *wsConn) protocolError(msg string) error {
+	c.writeFrame(wsClose, []byte{0x03, 0xEA})
+	return errors.New(msg)
+}
+
+func (c *wsConn) writeText(s string) error {
+	return c.writeFrame(wsText, []byte(s))
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	head := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		head = append(head, byte(n))
+	case n <= 0xFFFF:
+		head = append(head, 126)
+		head = binary.BigEndian.AppendUint16(head, uint16(n))
+	default:
+		head = append(head, 127)
+		head = binary.BigEndian.AppendUint64(head, uint64(n))
+	}
+	if err := c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	if _, err := c.conn.Write(append(head, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// clientFrame builds a frame the way a browser sends it, masked unless told
+// otherwise.
+func clientFrame(fin bool, opcode byte, payload []byte, masked bool) []byte {
+	head := []byte{opcode}
+	if fin {
+		head[0] |= 0x80
+	}
+	var maskBit byte
+	if masked {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		head = append(head, maskBit|byte(n))
+	case n <= 0xFFFF:
+		head = append(head, maskBit|126)
+		head = binary.BigEndian.AppendUint16(head, uint16(n))
+	default:
+		head = append(head, maskBit|127)
+		head = binary.BigEndian.AppendUint64(head, uint64(n))
+	}
+	if !masked {
+		return append(head, payload...)
+	}
+	mask := []byte{0x12, 0x34, 0x56, 0x78}
+	head = append(head, mask...)
+	for i, b := range payload {
+		head = append(head, b^mask[i%4])
+	}
+	return head
+}
+
+func TestWSReadMessage(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	tests := []struct {
+		name    string
+		frames  [][]byte
+		want    string
+		wantErr string // Substring of the error, "" for none
+	}{
+		{"text", [][]byte{clientFrame(true, wsText, []byte("hello"), true)}, "hello", ""},
+		{"16-bit length", [][]byte{clientFrame(true, wsText, []byte(long), true)}, long, ""},
+		{"fragmented", [][]byte{
+			clientFrame(false, wsText, []byte("hel"), true),
+			clientFrame(true, wsContinuation, []byte("lo"), true),
+		}, "hello", ""},
+		{"ping between fragments", [][]byte{
+			clientFrame(false, wsText, []byte("hel"), true),
+			clientFrame(true, wsPing, []byte("p"), true),
+			clientFrame(true, wsContinuation, []byte("lo"), true),
+		}, "hello", ""},
+		{"pong ignored", [][]byte{
+			clientFrame(true, wsPong, nil, true),
+			clientFrame(true, wsText, []byte("hi"), true),
+		}, "hi", ""},
+		{"unmasked", [][]byte{clientFrame(true, wsText, []byte("hi"), false)}, "", "unmasked"},
+		{"continuation first", [][]byte{clientFrame(true, wsContinuation, []byte("hi"), true)}, "", "out of place"},
+		{"text inside fragments", [][]byte{
+			clientFrame(false, wsText, []byte("a"), true),
+			clientFrame(true, wsText, []byte("b"), true),
+		}, "", "out of place"},
+		{"fragmented ping", [][]byte{clientFrame(false, wsPing, nil, true)}, "", "control frame"},
+		{"long ping", [][]byte{clientFrame(true, wsPing, []byte(long), true)}, "", "control frame"},
+		{"too big", [][]byte{{0x81, 0xFF, 0, 0, 0, 0, 0, 0x10, 0, 0}}, "", "over"},
+		{"fragments too big", [][]byte{
+			clientFrame(false, wsText, make([]byte, maxWSMessage-1), true),
+			clientFrame(true, wsContinuation, []byte("ab"), true),
+		}, "", "over"},
+		{"binary", [][]byte{clientFrame(true, wsBinary, []byte{1}, true)}, "", "binary"},
+		{"unknown opcode", [][]byte{clientFrame(true, 0x3, nil, true)}, "", "opcode"},
+		{"close", [][]byte{clientFrame(true, wsClose, nil, true)}, "", io.EOF.Error()},
+	}
+	for _, tt := range tests {
+		server, client := net.Pipe()
+		go io.Copy(io.Discard, client) // Pongs and close frames
+		go client.Write(bytes.Join(tt.frames, nil))
+		c := &wsConn{conn: server, br: bufio.NewReader(server)}
+		got, err := c.readMessage()
+		server.Close()
+		client.Close()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: readMessage() error %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: readMessage() error %v, want %q", tt.name, err, tt.wantErr)
+		case got != tt.want:
+			t.Errorf("%s: readMessage() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWSWriteFrame(t *testing.T) {
+	tests := []struct {
+		size int
+		head []byte
+	}{
+		{0, []byte{0x81, 0}},
+		{125, []byte{0x81, 125}},
+		{126, []byte{0x81, 126, 0, 126}},
+		{0xFFFF, []byte{0x81, 126, 0xFF, 0xFF}},
+		{0x10000, []byte{0x81, 127, 0, 0, 0, 0, 0, 1, 0, 0}},
+	}
+	for _, tt := range tests {
+		server, client := net.Pipe()
+		c := &wsConn{conn: server}
+		payload := strings.Repeat("a", tt.size)
+		errc := make(chan error, 1)
+		go func() { errc <- c.writeText(payload) }()
+		frame := make([]byte, len(tt.head)+tt.size)
+		if _, err := io.ReadFull(client, frame); err != nil {
+			t.Fatalf("reading a %d byte frame: %v", tt.size, err)
+		}
+		if err := <-errc; err != nil {
+			t.Errorf("writeText(%d bytes) error %v", tt.size, err)
+		}
+		if !bytes.Equal(frame[:len(tt.head)], tt.head) || string(frame[len(tt.head):]) != payload {
+			t.Errorf("writeText(%d bytes) header = %v, want %v", tt.size, frame[:len(tt.head)], tt.head)
+		}
+		server.Close()
+		client.Close()
+	}
+}
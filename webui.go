@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// --- Web UI ---
+// `fontlet serve` also serves a one-page live preview at /. The page sends
+// the form over the /live WebSocket on every keystroke; the server debounces
+// like the TUI's render-on-highlight (see highlightDebounce) and answers with
+// the latest render only, so fast typing costs one figlet run per pause.
+// Idle sockets are pinged to tell them from dead ones (see wsReadTimeout).
+
+func (s *server) handleWebUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, webUIPage)
+}
+
+func (s *server) handleLive(w http.ResponseWriter, r *http.Request) {
+	select {
+	case s.sockets <- struct{}{}:
+		defer func() { <-s.sockets }()
+	default:
+		http.Error(w, "too many live connections", http.StatusServiceUnavailable)
+		return
+	}
+	ws, err := upgradeWebSocket(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ws.Close()
+
+	// The reader keeps only the newest request; older ones are superseded.
+	requests := make(chan renderRequest, 1)
+	go func() {
+		defer close(requests)
+		for {
+			msg, err := ws.readMessage()
+			if err != nil {
+				return
+			}
+			var req renderRequest
+			if err := json.Unmarshal([]byte(msg), &req); err != nil {
+				ws.writeText(`{"error":"invalid request"}`)
+				continue
+			}
+			select {
+			case <-requests:
+			default:
+			}
+			requests <- req
+		}
+	}()
+
+	ip := clientIP(r, s.limits.trustProxy)
+	debounce := time.NewTimer(highlightDebounce)
+	debounce.Stop()
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	var pending *renderRequest
+	for {
+		select {
+		case <-ping.C:
+			if err := ws.writeFrame(wsPing, nil); err != nil {
+				return
+			}
+		case req, ok := <-requests:
+			if !ok {
+				return
+			}
+			pending = &req
+			debounce.Reset(highlightDebounce)
+		case <-debounce.C:
+			if pending == nil {
+				continue
+			}
+			var res renderResult
+			if ok, _ := s.allowIP(ip); !ok {
+				res.Error = "rate limit exceeded, pausing previews"
+			} else if res, err = s.render(r.Context(), *pending); err != nil {
+				res.Error = err.Error()
+			}
+			pending = nil
+			data, _ := json.Marshal(res)
+			if err := ws.writeText(string(data)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+const webUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Fontlet</title>
+<style>
+  body { font-family: sans-serif; margin: 2em; background: #1e1e2e; color: #cdd6f4; }
+  form { display: flex; gap: .5em; flex-wrap: wrap; }
+  input, select { font: inherit; padding: .3em; }
+  #text { flex: 1; min-width: 12em; }
+  pre { font-family: monospace; overflow-x: auto; }
+  #status { color: #f38ba8; min-height: 1.2em; }
+</style>
+</head>
+<body>
+<form id="form" onsubmit="return false">
+  <input id="text" placeholder="Type some text" autofocus>
+  <select id="font"><option>random</option></select>
+  <input id="width" type="number" value="80" min="1" size="4" title="Width">
+  <select id="filter"><option value="">no filter</option><option>braille</option><option>half-height</option></select>
+</form>
+<p id="status"></p>
+<pre id="output"></pre>
+<script>
+const $ = id => document.getElementById(id);
+fetch("fonts").then(r => r.json()).then(fonts => {
+  for (const f of fonts) $("font").add(new Option(f.name));
+  $("font").value = fonts.some(f => f.name === "standard") ? "standard" : "random";
+});
+let ws;
+function connect() {
+  ws = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + location.pathname.replace(/[^/]*$/, "live"));
+  ws.onmessage = e => {
+    const res = JSON.parse(e.data);
+    $("status").textContent = res.error || "";
+    if (!res.error) $("output").textContent = res.output;
+  };
+  ws.onopen = send;
+  ws.onclose = () => { $("status").textContent = "Disconnected, reconnecting…"; setTimeout(connect, 2000); };
+}
+function send() {
+  if (!$("text").value || ws.readyState !== WebSocket.OPEN) return;
+  ws.send(JSON.stringify({font: $("font").value, text: $("text").value, width: +$("width").value, filter: $("filter").value}));
+}
+$("form").addEventListener("input", send);
+connect();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// --- Width Presets ---
+// While viewing the output, 1, 2 and 3 re-render it at 80, 100 and 120
+// columns and 0 at the terminal's width again; w flips through them. Renders
+// are kept per width until the next full render, so flipping back and forth
+// to see how a banner degrades when narrower is instant. Saves use the width
+// being viewed.
+
+var widthPresets = []struct {
+	Key   string
+	Width int // 0 follows the terminal
+}{{"0", 0}, {"1", 80}, {"2", 100}, {"3", 120}}
+
+type widthRenderedMsg struct {
+	fontPath, text string // What was rendered, to drop renders finishing after a change
+	width          int
+	output         string
+}
+
+// setRenderWidth shows the output at width, rendering it unless it was
+// already rendered at that width.
+func (m model) setRenderWidth(width int) (model, tea.Cmd) {
+	if m.widthRenders == nil {
+		m.widthRenders = make(map[int]string)
+	}
+	m.widthRenders[m.fullRenderWidth()] = m.fullFigletOutput
+	m.renderWidth = width
+	renderWidth := m.fullRenderWidth()
+	if output, ok := m.widthRenders[renderWidth]; ok {
+		return m.showWidthRender(output), nil
+	}
+	figletCmdPath, fontPath, text := m.figletCmdPath, m.selectedFontMeta.Path, m.renderText()
+	return m, func() tea.Msg {
+		output, err := runFiglet(figletCmdPath, fontPath, text, renderWidth)
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to run figlet for full output: %w", err)}
+		}
+		return widthRenderedMsg{fontPath, text, renderWidth, output}
+	}
+}
+
+// showWidthRender makes output, rendered at the current width, the output.
+func (m model) showWidthRender(output string) model {
+	m.fullFigletOutput = output
+	m.unsaved = true
+	m.figletViewport.SetContent(m.outputText())
+	m.logAction("Re-rendered %s at %d columns", m.selectedFontMeta.Name, m.fullRenderWidth())
+	return m
+}
+
+// updateWidthKeys handles the width keys in the output view, reporting whether msg was one.
+func (m model) updateWidthKeys(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	if m.composed { // A composition's blocks keep the widths they were rendered at
+		return m, nil, false
+	}
+	if key.Matches(msg, m.keys.CycleWidth) {
+		next := widthPresets[0].Width
+		for i, p := range widthPresets {
+			if p.Width == m.renderWidth {
+				next = widthPresets[(i+1)%len(widthPresets)].Width
+			}
+		}
+		m, cmd := m.setRenderWidth(next)
+		return m, cmd, true
+	}
+	for _, p := range widthPresets {
+		if msg.String() == p.Key {
+			m, cmd := m.setRenderWidth(p.Width)
+			return m, cmd, true
+		}
+	}
+	return m, nil, false
+}
+
+// widthHelp lists the width keys with the width being viewed.
+func (m model) widthHelp() []key.Binding {
+	if m.composed {
+		return nil
+	}
+	return []key.Binding{infoBinding("1/2/3/0", "80/100/120/fit"), describe(m.keys.CycleWidth, fmt.Sprintf("width %d", m.fullRenderWidth()))}
+}